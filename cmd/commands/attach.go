@@ -6,15 +6,17 @@ import (
 	"github.com/kubemq-io/kubemqctl/pkg/attach"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type CommandsAttachOptions struct {
-	cfg       *config.Config
-	include   []string
-	exclude   []string
-	resources []string
+	cfg           *config.Config
+	include       []string
+	exclude       []string
+	resources     []string
+	schemaOptions schema.Options
 }
 
 var commandsAttachExamples = `
@@ -59,6 +61,7 @@ func NewCmdCommandsAttach(ctx context.Context, cfg *config.Config) *cobra.Comman
 
 	cmd.PersistentFlags().StringArrayVarP(&o.include, "include", "i", []string{}, "Set (regex) strings to include")
 	cmd.PersistentFlags().StringArrayVarP(&o.exclude, "exclude", "e", []string{}, "Set (regex) strings to exclude")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -81,7 +84,7 @@ func (o *CommandsAttachOptions) Validate() error {
 }
 
 func (o *CommandsAttachOptions) Run(ctx context.Context) error {
-	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude)
+	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude, &o.schemaOptions)
 	if err != nil {
 		return err
 	}
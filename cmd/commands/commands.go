@@ -16,6 +16,18 @@ var commandsExamples = `
 
 	# Execute attach to 'commands' channel
 	kubemqctl commands attach
+
+	# Execute test 'commands' consumer group load balancing command
+	kubemqctl commands group-test
+
+	# Execute mock 'commands' responder from a rules file command
+	kubemqctl commands mock
+
+	# Execute serve 'commands' with a process or http endpoint command
+	kubemqctl commands serve
+
+	# Execute probe 'commands' channel responder latency and SLO command
+	kubemqctl commands probe
 `
 var commandsLong = `Execute Kubemq 'commands' RPC commands`
 var commandsShort = `Execute Kubemq 'commands' RPC commands`
@@ -28,7 +40,7 @@ func NewCmdCommands(ctx context.Context, cfg *config.Config) *cobra.Command {
 		Short:     commandsShort,
 		Long:      commandsLong,
 		Example:   commandsExamples,
-		ValidArgs: []string{"send", "receive", "attach"},
+		ValidArgs: []string{"send", "receive", "attach", "group-test", "mock", "serve", "probe"},
 		Run: func(cmd *cobra.Command, args []string) {
 			utils.CheckErr(cmd.Help())
 		},
@@ -36,5 +48,9 @@ func NewCmdCommands(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.AddCommand(NewCmdCommandsSend(ctx, cfg))
 	cmd.AddCommand(NewCmdCommandsReceive(ctx, cfg))
 	cmd.AddCommand(NewCmdCommandsAttach(ctx, cfg))
+	cmd.AddCommand(NewCmdCommandsGroupTest(ctx, cfg))
+	cmd.AddCommand(NewCmdCommandsMock(ctx, cfg))
+	cmd.AddCommand(NewCmdCommandsServe(ctx, cfg))
+	cmd.AddCommand(NewCmdCommandsProbe(ctx, cfg))
 	return cmd
 }
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/grouptest"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type CommandsGroupTestOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	group     string
+	members   int
+	messages  int
+	timeout   int
+}
+
+var commandsGroupTestExamples = `
+	# Check how 100 'commands' are distributed across 4 members of group G1 on channel 'some-channel'
+	kubemqctl commands group-test some-channel -g G1 --members 4
+
+	# Check distribution of 1000 'commands' across 8 members with 2 seconds command timeout
+	kubemqctl commands group-test some-channel -g G1 --members 8 --messages 1000 --timeout 2
+`
+var commandsGroupTestLong = `Group-test command allows to verify consumer group load balancing of a 'commands' channel. It subscribes N responders in the same group, sends test commands and reports how the commands were distributed across the members, and whether any command was duplicated or lost`
+var commandsGroupTestShort = `Test 'commands' consumer group load balancing command`
+
+func NewCmdCommandsGroupTest(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &CommandsGroupTestOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "group-test",
+		Aliases: []string{"gt"},
+		Short:   commandsGroupTestShort,
+		Long:    commandsGroupTestLong,
+		Example: commandsGroupTestExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'commands' channel consumer group to test")
+	cmd.PersistentFlags().IntVarP(&o.members, "members", "m", 3, "set number of group members to subscribe")
+	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "n", 100, "set number of test commands to send")
+	cmd.PersistentFlags().IntVarP(&o.timeout, "timeout", "o", 5, "set command timeout in seconds")
+	return cmd
+}
+
+func (o *CommandsGroupTestOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+		return nil
+	}
+	return fmt.Errorf("missing channel argument")
+}
+
+func (o *CommandsGroupTestOptions) Validate() error {
+	if o.group == "" {
+		return fmt.Errorf("missing group, set group with -g")
+	}
+	if o.members <= 0 || o.messages <= 0 {
+		return fmt.Errorf("members and messages must be positive numbers")
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("timeout must be a positive number")
+	}
+	return nil
+}
+
+func (o *CommandsGroupTestOptions) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracker := grouptest.NewTracker(o.members)
+	errChan := make(chan error, o.members)
+	for i := 0; i < o.members; i++ {
+		client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+		if err != nil {
+			return fmt.Errorf("create kubemq client, %s", err.Error())
+		}
+		defer func(client *kubemq2.Client) {
+			client.Close()
+		}(client)
+		commandsChan, err := client.SubscribeToCommands(ctx, o.channel, o.group, errChan)
+		if err != nil {
+			return fmt.Errorf("subscribe member %d, %s", i+1, err.Error())
+		}
+		go func(member int, client *kubemq2.Client, commandsChan <-chan *kubemq2.CommandReceive) {
+			for {
+				select {
+				case command, opened := <-commandsChan:
+					if !opened {
+						return
+					}
+					if !tracker.Received(member, command.Id) {
+						continue
+					}
+					_ = client.R().
+						SetRequestId(command.Id).
+						SetResponseTo(command.ResponseTo).
+						SetExecutedAt(time.Now()).
+						SetBody([]byte(fmt.Sprintf("executed by member %d", member+1))).
+						Send(ctx)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i, client, commandsChan)
+	}
+	// subscriptions are registered by the server asynchronously, commands sent before are not delivered
+	time.Sleep(time.Second)
+
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	utils.Printlnf("sending %d 'commands' to channel %s, group %s with %d members...", o.messages, o.channel, o.group, o.members)
+	for i := 0; i < o.messages; i++ {
+		select {
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		default:
+		}
+		// failed commands are reported as lost
+		_, _ = client.C().
+			SetId(tracker.NextId()).
+			SetChannel(o.channel).
+			SetMetadata("kubemqctl group-test").
+			SetBody([]byte(fmt.Sprintf("group-test command %d", i+1))).
+			SetTimeout(time.Duration(o.timeout) * time.Second).
+			Send(ctx)
+	}
+	report := tracker.Report()
+	report.Print(os.Stdout)
+	if !report.Ok() {
+		return fmt.Errorf("group-test failed, %d commands duplicated, %d commands lost", report.Duplicated, report.Lost)
+	}
+	utils.Println("group-test passed, each command was delivered to exactly one member")
+	return nil
+}
@@ -5,21 +5,23 @@ import (
 	"encoding/json"
 	"fmt"
 	kubemq "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"strconv"
 )
 
 type object struct {
-	Id         string            `json:"id"`
-	Channel    string            `json:"channel,omitempty"`
-	ClientId   string            `json:"client_id,omitempty"`
-	Metadata   string            `json:"metadata,omitempty"`
-	Tags       map[string]string `json:"tags,omitempty"`
-	Executed   string            `json:"executed,omitempty"`
-	Timeout    string            `json:"timeout,omitempty"`
-	ExecutedAt string            `json:"executed_at,omitempty"`
-	Error      string            `json:"error,omitempty"`
-	BodyJson   json.RawMessage   `json:"body_json,omitempty"`
-	BodyString string            `json:"body_string,omitempty"`
+	Id          string            `json:"id"`
+	Channel     string            `json:"channel,omitempty"`
+	ClientId    string            `json:"client_id,omitempty"`
+	Metadata    string            `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Executed    string            `json:"executed,omitempty"`
+	Timeout     string            `json:"timeout,omitempty"`
+	ExecutedAt  string            `json:"executed_at,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	BodyJson    json.RawMessage   `json:"body_json,omitempty"`
+	BodyString  string            `json:"body_string,omitempty"`
+	SchemaError string            `json:"schema_error,omitempty"`
 }
 
 func newObjectWithCommandReceive(cmd *kubemq.CommandReceive) *object {
@@ -104,8 +106,10 @@ func (o *object) String() string {
 	return string(data)
 }
 
-func printCommandReceive(command *kubemq.CommandReceive) {
-	fmt.Println(newObjectWithCommandReceive(command))
+func printCommandReceive(command *kubemq.CommandReceive, v *schema.Validator) {
+	obj := newObjectWithCommandReceive(command)
+	obj.SchemaError = schema.Error(v, command.Body)
+	fmt.Println(obj)
 }
 
 func printCommandResponse(response *kubemq.CommandResponse) {
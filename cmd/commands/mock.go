@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/mock"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type CommandsMockOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	group     string
+	rules     string
+	quiet     bool
+	responder *mock.Responder
+	mu        sync.Mutex
+}
+
+var commandsMockExamples = `
+	# Answer 'commands' of a 'commands' channel with the responses of the first matching rule of a rules file
+	kubemqctl commands mock some-channel --rules mock.yaml
+
+	# Answer 'commands' of a 'commands' channel with group, printing only the matched rules
+	kubemqctl commands mock some-channel -g G1 --rules mock.yaml --quiet
+
+	# Rules file example, rules match requests by metadata, body and jsonpath regular expressions and respond with an
+	# executed response with templated metadata (functions are uuid, now, seq, randInt, randChoice, env and file), an error or a delay
+	rules:
+	  - name: ship-order
+	    match:
+	      metadata: ^ship$
+	      jsonpath:
+	        - path: $.type
+	          value: ^order$
+	    response:
+	      metadata: 'shipped {{.JSONPath "$.id"}}'
+	      delay: 100ms
+	  - name: everything-else
+	    response:
+	      error: unknown request {{.Metadata}}
+`
+var commandsMockLong = `Mock command allows to answer the 'commands' of a channel from a rules file to stub services in tests, matched rules send executed or error responses and requests not matching any rule are answered with an error`
+var commandsMockShort = `Mock a 'commands' channel responder command`
+
+func NewCmdCommandsMock(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &CommandsMockOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "mock",
+		Aliases: []string{"m"},
+		Short:   commandsMockShort,
+		Long:    commandsMockLong,
+		Example: commandsMockExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'commands' channel consumer group (load balancing)")
+	cmd.PersistentFlags().StringVarP(&o.rules, "rules", "r", "", "set mock rules yaml file")
+	cmd.PersistentFlags().BoolVarP(&o.quiet, "quiet", "q", false, "set print only the matched rule of each command")
+	return cmd
+}
+
+func (o *CommandsMockOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *CommandsMockOptions) Validate() error {
+	if o.rules == "" {
+		return fmt.Errorf("missing rules file, set --rules flag")
+	}
+	var err error
+	o.responder, err = mock.Load(o.rules)
+	return err
+}
+
+func (o *CommandsMockOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	errChan := make(chan error, 1)
+	commandsChan, err := client.SubscribeToCommands(ctx, o.channel, o.group, errChan)
+	if err != nil {
+		return fmt.Errorf("receive 'commands' messages, %s", err.Error())
+	}
+	utils.Printlnf("mocking 'commands' of %s with rules of %s...", o.channel, o.rules)
+	for {
+		select {
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case command, opened := <-commandsChan:
+			if !opened {
+				utils.Println("server disconnected")
+				return nil
+			}
+			// commands are answered concurrently so delayed responses do not hold the next commands
+			go o.respond(ctx, client, command)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (o *CommandsMockOptions) respond(ctx context.Context, client *kubemq2.Client, command *kubemq2.CommandReceive) {
+	res, err := o.responder.Respond(&mock.Request{
+		Id:       command.Id,
+		Channel:  command.Channel,
+		Metadata: command.Metadata,
+		Body:     string(command.Body),
+		Tags:     command.Tags,
+	})
+	resp := client.R().SetRequestId(command.Id).SetResponseTo(command.ResponseTo)
+	result := ""
+	switch {
+	case err != nil:
+		resp.SetError(err)
+		result = fmt.Sprintf("rule error: %s", err.Error())
+	case res == nil:
+		resp.SetError(fmt.Errorf("no mock rule matched"))
+		result = "no rule matched, error response sent"
+	default:
+		if res.Delay > 0 {
+			select {
+			case <-time.After(res.Delay):
+			case <-ctx.Done():
+				return
+			}
+		}
+		res.Apply(resp)
+		if res.Error != "" {
+			result = fmt.Sprintf("rule %s matched, error response sent: %s", res.Rule, res.Error)
+		} else {
+			result = fmt.Sprintf("rule %s matched, executed response sent", res.Rule)
+		}
+	}
+	sendErr := resp.Send(ctx)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.quiet {
+		printCommandReceive(command, nil)
+	}
+	if sendErr != nil {
+		utils.Printlnf("command %s: %s, send failed, %s", command.Id, result, sendErr.Error())
+		return
+	}
+	utils.Printlnf("command %s: %s", command.Id, result)
+}
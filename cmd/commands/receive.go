@@ -7,17 +7,20 @@ import (
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"time"
 )
 
 type CommandsReceiveOptions struct {
-	cfg          *config.Config
-	transport    string
-	channel      string
-	group        string
-	autoResponse bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	group         string
+	autoResponse  bool
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var commandsReceiveExamples = `
@@ -53,6 +56,7 @@ func NewCmdCommandsReceive(ctx context.Context, cfg *config.Config) *cobra.Comma
 
 	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'commands' channel consumer group (load balancing)")
 	cmd.PersistentFlags().BoolVarP(&o.autoResponse, "auto-response", "a", false, "set auto response executed command for each command received")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -60,9 +64,14 @@ func (o *CommandsReceiveOptions) Complete(args []string, transport string) error
 	o.transport = transport
 	if len(args) >= 1 {
 		o.channel = args[0]
-		return nil
+	} else {
+		return fmt.Errorf("missing channel argument")
 	}
-	return fmt.Errorf("missing channel argument")
+	var err error
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (o *CommandsReceiveOptions) Validate() error {
@@ -95,7 +104,7 @@ func (o *CommandsReceiveOptions) Run(ctx context.Context) error {
 				utils.Println("server disconnected")
 				return nil
 			}
-			printCommandReceive(command)
+			printCommandReceive(command, o.validator)
 			if o.autoResponse {
 				err = client.R().SetRequestId(command.Id).SetExecutedAt(time.Now()).SetResponseTo(command.ResponseTo).Send(ctx)
 				if err != nil {
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/targets"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -14,14 +16,16 @@ import (
 )
 
 type CommandsSendOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	body      string
-	metadata  string
-	timeout   int
-	fileName  bool
-	build     bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	body          string
+	metadata      string
+	timeout       int
+	fileName      bool
+	build         bool
+	noTemplate    bool
+	schemaOptions schema.Options
 }
 
 var commandsSendExamples = `
@@ -33,6 +37,15 @@ var commandsSendExamples = `
 	
 	# Send command to a 'commands' channel with 120 seconds timeout
 	kubemqctl commands send some-channel some-body -o 120
+
+	# Send command to a 'commands' channel after validating the body against a JSON Schema
+	kubemqctl commands send some-channel '{"action":"restart"}' --schema command.schema.json
+
+	# Send command with templated body, functions are uuid, now, seq, randInt, randChoice, env and file
+	kubemqctl commands send some-channel '{"id":"{{uuid}}","user":"{{env "USER"}}"}'
+
+	# Send command with a body containing literal {{ }} braces without templating
+	kubemqctl commands send some-channel 'Hello {{ .Name }}' --no-template
 `
 var commandsSendLong = `Send command allow to send messages to 'commands' channel with an option to set command time-out`
 var commandsSendShort = `Send messages to 'commands' channel command`
@@ -61,6 +74,8 @@ func NewCmdCommandsSend(ctx context.Context, cfg *config.Config) *cobra.Command
 	cmd.PersistentFlags().IntVarP(&o.timeout, "timeout", "o", 30, "Set command timeout")
 	cmd.PersistentFlags().BoolVarP(&o.fileName, "file", "f", false, "set load body from file")
 	cmd.PersistentFlags().BoolVarP(&o.build, "build", "b", false, "build kubemq targets request")
+	cmd.PersistentFlags().BoolVarP(&o.noTemplate, "no-template", "", false, "set send body and metadata as is without rendering {{ }} template actions")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -97,7 +112,16 @@ func (o *CommandsSendOptions) Complete(args []string, transport string) error {
 }
 
 func (o *CommandsSendOptions) Validate() error {
-	return nil
+	message, err := generator.NewMessage(o.body, o.metadata, !o.noTemplate && !o.fileName && !o.build, !o.noTemplate)
+	if err != nil {
+		return err
+	}
+	body, metadata, err := message.Render(1)
+	if err != nil {
+		return err
+	}
+	o.body, o.metadata = string(body), metadata
+	return o.schemaOptions.ValidateBody(o.cfg, o.channel, body)
 }
 
 func (o *CommandsSendOptions) Run(ctx context.Context) error {
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/handler"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type CommandsServeOptions struct {
+	cfg            *config.Config
+	transport      string
+	channel        string
+	group          string
+	handlerOptions handler.Options
+	handler        handler.Handler
+	mu             sync.Mutex
+}
+
+var commandsServeExamples = `
+	# Serve 'commands' of a 'commands' channel with a process, each command is written as json to the process stdin
+	# and the process prints the response json, i.e. {"metadata":"done"} or {"error":"failed"}, to its stdout.
+	# Bodies which are not valid UTF-8 are passed base64 encoded in the body_base64 field
+	kubemqctl commands serve some-channel --exec ./handler
+
+	# Serve 'commands' of a 'commands' channel with an http endpoint, each command json is posted to the endpoint
+	kubemqctl commands serve some-channel --http http://localhost:8081/handle
+
+	# Serve 'commands' with group, handling up to 50 commands at once with 5 seconds timeout each
+	kubemqctl commands serve some-channel -g G1 --http http://localhost:8081/handle -c 50 -t 5s
+`
+var commandsServeLong = `Serve command allows to answer the 'commands' of a channel with an external process or http endpoint, handled commands are sent executed responses and failed or timed out commands are sent error responses`
+var commandsServeShort = `Serve a 'commands' channel with a process or http endpoint command`
+
+func NewCmdCommandsServe(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &CommandsServeOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "serve",
+		Aliases: []string{"sv"},
+		Short:   commandsServeShort,
+		Long:    commandsServeLong,
+		Example: commandsServeExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'commands' channel consumer group (load balancing)")
+	o.handlerOptions.AddFlags(cmd)
+	return cmd
+}
+
+func (o *CommandsServeOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *CommandsServeOptions) Validate() error {
+	var err error
+	o.handler, err = o.handlerOptions.Handler()
+	return err
+}
+
+func (o *CommandsServeOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	errChan := make(chan error, 1)
+	commandsChan, err := client.SubscribeToCommands(ctx, o.channel, o.group, errChan)
+	if err != nil {
+		return fmt.Errorf("receive 'commands' messages, %s", err.Error())
+	}
+	utils.Printlnf("serving 'commands' of %s with %s...", o.channel, o.handler)
+	// the semaphore bounds the commands handled at once, the next command is received when a handler is free
+	sem := make(chan struct{}, o.handlerOptions.Concurrency)
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+	for {
+		select {
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case command, opened := <-commandsChan:
+			if !opened {
+				utils.Println("server disconnected")
+				return nil
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			wg.Add(1)
+			go func(command *kubemq2.CommandReceive) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				o.serve(ctx, client, command)
+			}(command)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (o *CommandsServeOptions) serve(ctx context.Context, client *kubemq2.Client, command *kubemq2.CommandReceive) {
+	handlerCtx, cancel := context.WithTimeout(ctx, o.handlerOptions.Timeout)
+	defer cancel()
+	start := time.Now()
+	req := &handler.Request{
+		Id:       command.Id,
+		Type:     "command",
+		Channel:  command.Channel,
+		ClientId: command.ClientId,
+		Metadata: command.Metadata,
+		Tags:     command.Tags,
+	}
+	res, err := o.handler.Handle(handlerCtx, req.SetBody(command.Body))
+	if err != nil {
+		res = &handler.Response{
+			Error: err.Error(),
+		}
+	}
+	sendErr := res.Apply(client.R().SetRequestId(command.Id).SetResponseTo(command.ResponseTo)).Send(ctx)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	result := "executed"
+	if res.Error != "" {
+		result = fmt.Sprintf("error: %s", res.Error)
+	}
+	if sendErr != nil {
+		utils.Printlnf("command %s: %s in %s, send response failed, %s", command.Id, result, time.Since(start), sendErr.Error())
+		return
+	}
+	utils.Printlnf("command %s: %s in %s", command.Id, result, time.Since(start))
+}
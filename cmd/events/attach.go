@@ -6,15 +6,17 @@ import (
 	"github.com/kubemq-io/kubemqctl/pkg/attach"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type EventsAttachOptions struct {
-	cfg       *config.Config
-	include   []string
-	exclude   []string
-	resources []string
+	cfg           *config.Config
+	include       []string
+	exclude       []string
+	resources     []string
+	schemaOptions schema.Options
 }
 
 var eventsAttachExamples = `
@@ -58,6 +60,7 @@ func NewCmdEventsAttach(ctx context.Context, cfg *config.Config) *cobra.Command
 	}
 	cmd.PersistentFlags().StringArrayVarP(&o.include, "include", "i", []string{}, "set (regex) strings to include")
 	cmd.PersistentFlags().StringArrayVarP(&o.exclude, "exclude", "e", []string{}, "set (regex) strings to exclude")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -80,7 +83,7 @@ func (o *EventsAttachOptions) Validate() error {
 }
 
 func (o *EventsAttachOptions) Run(ctx context.Context) error {
-	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude)
+	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude, &o.schemaOptions)
 	if err != nil {
 		return err
 	}
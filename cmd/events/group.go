@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/grouptest"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type EventsGroupTestOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	group     string
+	members   int
+	messages  int
+	wait      time.Duration
+}
+
+var eventsGroupTestExamples = `
+	# Check how 100 'events' are distributed across 4 members of group G1 on channel 'some-channel'
+	kubemqctl events group-test some-channel -g G1 --members 4
+
+	# Check distribution of 1000 'events' across 8 members, waiting up to 10 seconds for deliveries
+	kubemqctl events group-test some-channel -g G1 --members 8 --messages 1000 --wait 10s
+`
+var eventsGroupTestLong = `Group-test command allows to verify consumer group load balancing of an 'events' channel. It subscribes N members in the same group, publishes a burst of test messages and reports how the messages were distributed across the members, and whether any message was duplicated or lost`
+var eventsGroupTestShort = `Test 'events' consumer group load balancing command`
+
+func NewCmdEventsGroupTest(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &EventsGroupTestOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "group-test",
+		Aliases: []string{"gt"},
+		Short:   eventsGroupTestShort,
+		Long:    eventsGroupTestLong,
+		Example: eventsGroupTestExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'events' channel consumer group to test")
+	cmd.PersistentFlags().IntVarP(&o.members, "members", "m", 3, "set number of group members to subscribe")
+	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "n", 100, "set number of test messages to publish")
+	cmd.PersistentFlags().DurationVarP(&o.wait, "wait", "w", 5*time.Second, "set max time to wait for deliveries after publishing")
+	return cmd
+}
+
+func (o *EventsGroupTestOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+		return nil
+	}
+	return fmt.Errorf("missing channel argument")
+}
+
+func (o *EventsGroupTestOptions) Validate() error {
+	if o.group == "" {
+		return fmt.Errorf("missing group, set group with -g")
+	}
+	if o.members <= 0 || o.messages <= 0 {
+		return fmt.Errorf("members and messages must be positive numbers")
+	}
+	if o.wait <= 0 {
+		return fmt.Errorf("wait must be a positive duration")
+	}
+	return nil
+}
+
+func (o *EventsGroupTestOptions) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracker := grouptest.NewTracker(o.members)
+	errChan := make(chan error, o.members)
+	for i := 0; i < o.members; i++ {
+		client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+		if err != nil {
+			return fmt.Errorf("create kubemq client, %s", err.Error())
+		}
+		defer func(client *kubemq2.Client) {
+			client.Close()
+		}(client)
+		eventsChan, err := client.SubscribeToEvents(ctx, o.channel, o.group, errChan)
+		if err != nil {
+			return fmt.Errorf("subscribe member %d, %s", i+1, err.Error())
+		}
+		go func(member int, eventsChan <-chan *kubemq2.Event) {
+			for {
+				select {
+				case ev, opened := <-eventsChan:
+					if !opened {
+						return
+					}
+					tracker.Received(member, ev.Id)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i, eventsChan)
+	}
+	// subscriptions are registered by the server asynchronously, events published before are not delivered
+	time.Sleep(time.Second)
+
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	utils.Printlnf("publishing %d 'events' to channel %s, group %s with %d members...", o.messages, o.channel, o.group, o.members)
+	for i := 0; i < o.messages; i++ {
+		err := client.E().
+			SetId(tracker.NextId()).
+			SetChannel(o.channel).
+			SetMetadata("kubemqctl group-test").
+			SetBody([]byte(fmt.Sprintf("group-test message %d", i+1))).
+			Send(ctx)
+		if err != nil {
+			return fmt.Errorf("publish 'events' message, %s", err.Error())
+		}
+	}
+	if err := waitForDeliveries(ctx, tracker, o.wait, errChan); err != nil {
+		return err
+	}
+	report := tracker.Report()
+	report.Print(os.Stdout)
+	if !report.Ok() {
+		return fmt.Errorf("group-test failed, %d messages duplicated, %d messages lost", report.Duplicated, report.Lost)
+	}
+	utils.Println("group-test passed, each message was delivered to exactly one member")
+	return nil
+}
+
+// waitForDeliveries waits until every sent message was received, or the wait time passed
+func waitForDeliveries(ctx context.Context, tracker *grouptest.Tracker, wait time.Duration, errChan chan error) error {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for !tracker.Done() {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return nil
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	// duplicated deliveries may still be in flight
+	time.Sleep(500 * time.Millisecond)
+	return nil
+}
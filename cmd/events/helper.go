@@ -1,23 +1,26 @@
 package events
 
 import (
-	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	kubemq "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 )
 
 type object struct {
-	Id         string            `json:"id"`
-	Channel    string            `json:"channel,omitempty"`
-	ClientId   string            `json:"client_id,omitempty"`
-	Metadata   string            `json:"metadata,omitempty"`
-	Tags       map[string]string `json:"tags,omitempty"`
-	BodyJson   json.RawMessage   `json:"body_json,omitempty"`
-	BodyString string            `json:"body_string,omitempty"`
+	Id          string            `json:"id"`
+	Channel     string            `json:"channel,omitempty"`
+	ClientId    string            `json:"client_id,omitempty"`
+	Metadata    string            `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	BodyJson    json.RawMessage   `json:"body_json,omitempty"`
+	BodyString  string            `json:"body_string,omitempty"`
+	DecodeError string            `json:"decode_error,omitempty"`
+	SchemaError string            `json:"schema_error,omitempty"`
 }
 
-func newObjectWithEvent(event *kubemq.Event) *object {
+func newObjectWithEvent(event *kubemq.Event, dec decoder.Decoder) *object {
 	obj := &object{
 		Id:         event.Id,
 		Channel:    event.Channel,
@@ -27,25 +30,28 @@ func newObjectWithEvent(event *kubemq.Event) *object {
 		BodyJson:   json.RawMessage{},
 		BodyString: "",
 	}
-	var js json.RawMessage
-	if err := json.Unmarshal(event.Body, &js); err == nil {
-		obj.BodyJson = js
-	} else {
-		sDec, err := b64.StdEncoding.DecodeString(string(event.Body))
-		if err != nil {
-			obj.BodyString = string(event.Body)
-		} else {
-			obj.BodyString = string(sDec)
-		}
+	var err error
+	if obj.BodyJson, obj.BodyString, err = decoder.Body(dec, event.Body); err != nil {
+		obj.DecodeError = err.Error()
 	}
 	return obj
 }
 
+// withSchema validates the decoded body against the schema, a validation error is set as the object schema error
+func (o *object) withSchema(v *schema.Validator) *object {
+	body := []byte(o.BodyJson)
+	if len(body) == 0 {
+		body = []byte(o.BodyString)
+	}
+	o.SchemaError = schema.Error(v, body)
+	return o
+}
+
 func (o *object) String() string {
 	data, _ := json.MarshalIndent(o, "", "    ")
 	return string(data)
 }
 
-func printEvent(event *kubemq.Event) {
-	fmt.Println(newObjectWithEvent(event))
+func printEvent(event *kubemq.Event, dec decoder.Decoder, v *schema.Validator) {
+	fmt.Println(newObjectWithEvent(event, dec).withSchema(v))
 }
@@ -4,17 +4,23 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type EventsReceiveOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	group     string
+	cfg           *config.Config
+	transport     string
+	channel       string
+	group         string
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var eventsReceiveExamples = `
@@ -24,6 +30,12 @@ var eventsReceiveExamples = `
 	# Receive messages from an 'events' channel with group (blocks until next body)
 	kubemqctl events receive some-channel -g G1
 
+	# Receive messages from an 'events' channel with zstd compressed json body
+	kubemqctl events receive some-channel --decode zstd
+
+	# Receive messages from an 'events' channel and flag bodies not valid against a JSON Schema
+	kubemqctl events receive some-channel --schema order.schema.json
+
 `
 var eventsReceiveLong = `Receive (Subscribe) command allows to consume one or many messages from 'events' channel`
 var eventsReceiveShort = `Receive a body from 'events' channel command`
@@ -50,6 +62,8 @@ func NewCmdEventsReceive(ctx context.Context, cfg *config.Config) *cobra.Command
 	}
 
 	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'events' channel consumer group (load balancing)")
+	o.decodeOptions.AddFlags(cmd)
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -57,9 +71,17 @@ func (o *EventsReceiveOptions) Complete(args []string, transport string) error {
 	o.transport = transport
 	if len(args) >= 1 {
 		o.channel = args[0]
-		return nil
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
 	}
-	return fmt.Errorf("missing channel argument")
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (o *EventsReceiveOptions) Validate() error {
@@ -90,7 +112,7 @@ func (o *EventsReceiveOptions) Run(ctx context.Context) error {
 				utils.Println("server disconnected")
 				return nil
 			}
-			printEvent(ev)
+			printEvent(ev, o.decoder, o.validator)
 		case err := <-errChan:
 			return fmt.Errorf("server disconnected with error: %s", err.Error())
 		case <-ctx.Done():
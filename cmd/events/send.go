@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/google/uuid"
-	kubemq2 "github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/targets"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -15,15 +16,20 @@ import (
 )
 
 type EventsSendOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	body      string
-	metadata  string
-	messages  int
-	isStream  bool
-	fileName  bool
-	build     bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	body          string
+	metadata      string
+	messages      int
+	isStream      bool
+	fileName      bool
+	build         bool
+	noTemplate    bool
+	schemaOptions schema.Options
+	stdin         bool
+	flushTimeout  time.Duration
+	message       *generator.Message
 }
 
 var eventsSendExamples = `
@@ -38,6 +44,21 @@ var eventsSendExamples = `
 
 	# Send (Publish) batch of 100 messages to a 'events' channel in stream mode
 	kubemqctl events send some-channel some-body -m 100 -s
+
+	# Send (Publish) 100000 messages to a 'events' channel in stream mode waiting up to 30 seconds for delivery
+	kubemqctl events send some-channel some-body -m 100000 -s --flush-timeout 30s
+
+	# Send (Publish) body to a 'events' channel after validating the body against a JSON Schema
+	kubemqctl events send some-channel '{"id":"o-1"}' --schema order.schema.json
+
+	# Send (Publish) 1000 messages to a 'events' channel with templated body, functions are uuid, now, seq, randInt, randChoice, env and file
+	kubemqctl events send some-channel '{"id":"{{uuid}}","n":{{seq}},"region":"{{randChoice "eu" "us"}}"}' -m 1000
+
+	# Send (Publish) a body containing literal {{ }} braces to a 'events' channel without templating
+	kubemqctl events send some-channel 'Hello {{ .Name }}' --no-template
+
+	# Send (Publish) each line of stdin as a message body to a 'events' channel
+	tail -f app.log | kubemqctl events send logs -
 `
 var eventsSendLong = `Send command allows to send (publish) one or many messages to an 'events' channel`
 var eventsSendShort = `Send messages to an 'events' channel command`
@@ -65,8 +86,11 @@ func NewCmdEventsSend(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&o.metadata, "metadata", "", "", "set body metadata field")
 	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "m", 1, "set how many 'events' messages to send")
 	cmd.PersistentFlags().BoolVarP(&o.isStream, "stream", "s", false, "set stream of all messages at once")
+	cmd.PersistentFlags().DurationVarP(&o.flushTimeout, "flush-timeout", "", 10*time.Second, "set how long to wait for streamed messages to be flushed and failures reported (stream and stdin modes)")
 	cmd.PersistentFlags().BoolVarP(&o.fileName, "file", "f", false, "set body body from file")
 	cmd.PersistentFlags().BoolVarP(&o.build, "build", "b", false, "build kubemq targets request")
+	cmd.PersistentFlags().BoolVarP(&o.noTemplate, "no-template", "", false, "set send body and metadata as is without rendering {{ }} template actions")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -95,6 +119,7 @@ func (o *EventsSendOptions) Complete(args []string, transport string) error {
 	} else {
 		if len(args) >= 2 {
 			o.body = args[1]
+			o.stdin = o.body == "-"
 		} else {
 			return fmt.Errorf("missing body argument")
 		}
@@ -103,7 +128,34 @@ func (o *EventsSendOptions) Complete(args []string, transport string) error {
 }
 
 func (o *EventsSendOptions) Validate() error {
-	return nil
+	// stdin lines are validated one by one as they arrive
+	if o.stdin {
+		return nil
+	}
+	var err error
+	if o.message, err = generator.NewMessage(o.body, o.metadata, !o.noTemplate && !o.fileName && !o.build, !o.noTemplate); err != nil {
+		return err
+	}
+	// templated bodies are validated per message, the first message is validated before connecting
+	body, _, err := o.message.Render(1)
+	if err != nil {
+		return err
+	}
+	return o.schemaOptions.ValidateBody(o.cfg, o.channel, body)
+}
+
+// render returns the body and metadata of message seq, templated bodies are validated against the channel schema
+func (o *EventsSendOptions) render(seq int) ([]byte, string, error) {
+	body, metadata, err := o.message.Render(seq)
+	if err != nil {
+		return nil, "", err
+	}
+	if !o.message.IsStatic() {
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, body); err != nil {
+			return nil, "", fmt.Errorf("event %d, %s", seq, err.Error())
+		}
+	}
+	return body, metadata, nil
 }
 
 func (o *EventsSendOptions) Run(ctx context.Context) error {
@@ -115,38 +167,58 @@ func (o *EventsSendOptions) Run(ctx context.Context) error {
 	defer func() {
 		client.Close()
 	}()
-
+	if o.stdin {
+		return o.runStdin(ctx, client)
+	}
 	if o.isStream {
 		utils.Printlnf("Streaming %d events messages ...", o.messages)
-		eventsCh := make(chan *kubemq2.Event, 100)
-		errCh := make(chan error, 10)
+		stream := newEventsStream(ctx, client)
 		fmt.Println("Sending Stream Events:")
-		go client.StreamEvents(ctx, eventsCh, errCh)
 		startTime := time.Now()
+		var streamErr error
 		for i := 1; i <= o.messages; i++ {
+			body, metadata, err := o.render(i)
+			if err != nil {
+				stream.close(o.flushTimeout)
+				return err
+			}
 			msg := client.E().
 				SetChannel(o.channel).
 				SetId(uuid.New().String()).
-				SetBody([]byte(o.body)).
-				SetMetadata(o.metadata)
-			printEvent(msg)
-			eventsCh <- msg
+				SetBody(body).
+				SetMetadata(metadata)
+			printEvent(msg, nil, nil)
+			if streamErr = stream.send(ctx, msg); streamErr != nil {
+				break
+			}
+		}
+		if streamErr == nil {
+			streamErr = stream.close(o.flushTimeout)
+		}
+		utils.Printlnf("%d events messages streamed in %s, %d delivered, %d failed.", stream.sent, time.Since(startTime), stream.delivered(), stream.failed)
+		if streamErr != nil {
+			return fmt.Errorf("streaming 'events' messages, %s", streamErr.Error())
+		}
+		if stream.failed > 0 {
+			return fmt.Errorf("%d of %d 'events' messages failed", stream.failed, o.messages)
 		}
-		utils.Printlnf("%d events messages streamed in %s.", o.messages, time.Since(startTime))
-		time.Sleep(time.Second)
 	} else {
 		fmt.Println("Sending Events:")
 		for i := 1; i <= o.messages; i++ {
+			body, metadata, err := o.render(i)
+			if err != nil {
+				return err
+			}
 			msg := client.E().
 				SetChannel(o.channel).
 				SetId(uuid.New().String()).
-				SetBody([]byte(o.body)).
-				SetMetadata(o.metadata)
+				SetBody(body).
+				SetMetadata(metadata)
 			err = msg.Send(ctx)
 			if err != nil {
 				return fmt.Errorf("sending 'events' body, %s", err.Error())
 			}
-			printEvent(msg)
+			printEvent(msg, nil, nil)
 		}
 	}
 
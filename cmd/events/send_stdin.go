@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// runStdin publishes each stdin line as an 'events' message body as it arrives and reports the counts on EOF
+func (o *EventsSendOptions) runStdin(ctx context.Context, client *kubemq2.Client) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream := newEventsStream(ctx, client)
+	linesCh, readErrCh := utils.ReadLines(ctx, os.Stdin)
+	utils.Printlnf("streaming stdin lines to 'events' channel %s...", o.channel)
+	skipped, line := 0, 0
+	var streamErr error
+	for body := range linesCh {
+		line++
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, []byte(body)); err != nil {
+			utils.Printlnf("line %d skipped, %s", line, err.Error())
+			skipped++
+			continue
+		}
+		msg := client.E().
+			SetChannel(o.channel).
+			SetId(uuid.New().String()).
+			SetBody([]byte(body)).
+			SetMetadata(o.metadata)
+		if streamErr = stream.send(ctx, msg); streamErr != nil {
+			break
+		}
+	}
+	summary := func() {
+		utils.Printlnf("Summary: %d messages sent, %d delivered, %d failed, %d invalid lines skipped", stream.sent, stream.delivered(), stream.failed, skipped)
+	}
+	// interrupted runs report what was queued so far without waiting for the closed stream
+	if ctx.Err() != nil {
+		summary()
+		return nil
+	}
+	if streamErr == nil {
+		streamErr = stream.close(o.flushTimeout)
+	}
+	summary()
+	if streamErr != nil {
+		return fmt.Errorf("stream 'events' messages, %s", streamErr.Error())
+	}
+	select {
+	case err := <-readErrCh:
+		return fmt.Errorf("read stdin, %s", err.Error())
+	default:
+	}
+	if stream.failed > 0 {
+		return fmt.Errorf("%d messages failed to send", stream.failed)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"google.golang.org/grpc/status"
+)
+
+// eventsStream publishes events over one stream and accounts their delivery, the server reports only the events it
+// failed to deliver, so every event written to the stream without a failure report is accounted as delivered
+type eventsStream struct {
+	eventsCh  chan *kubemq2.Event
+	errCh     chan error
+	closed    chan struct{}
+	cancel    context.CancelFunc
+	sent      int
+	failed    int
+	streamErr error
+}
+
+func newEventsStream(ctx context.Context, client *kubemq2.Client) *eventsStream {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &eventsStream{
+		eventsCh: make(chan *kubemq2.Event, 100),
+		errCh:    make(chan error, 100),
+		closed:   make(chan struct{}),
+		cancel:   cancel,
+	}
+	go func() {
+		defer close(s.closed)
+		client.StreamEvents(ctx, s.eventsCh, s.errCh)
+	}()
+	return s
+}
+
+// send queues an event to the stream, an error is returned when the stream was closed by an error
+func (s *eventsStream) send(ctx context.Context, event *kubemq2.Event) error {
+	for {
+		select {
+		case <-s.closed:
+			return s.closedErr()
+		default:
+		}
+		select {
+		case s.eventsCh <- event:
+			s.sent++
+			return nil
+		case err := <-s.errCh:
+			s.failure(err)
+		case <-s.closed:
+			return s.closedErr()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// close waits up to timeout for the queued events to be written to the stream and for the server failure reports of
+// the written events, events still queued when the wait ends are accounted as failed
+func (s *eventsStream) close(timeout time.Duration) error {
+	defer s.cancel()
+	deadline := time.After(timeout)
+	for len(s.eventsCh) > 0 {
+		select {
+		case err := <-s.errCh:
+			s.failure(err)
+		case <-s.closed:
+			return s.closedErr()
+		case <-deadline:
+			s.failed += len(s.eventsCh)
+			return fmt.Errorf("%d events messages were not flushed in %s", len(s.eventsCh), timeout)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	// failure reports of the last written events arrive after the events left the queue
+	grace := time.After(time.Second)
+	for {
+		select {
+		case err := <-s.errCh:
+			s.failure(err)
+		case <-s.closed:
+			return s.closedErr()
+		case <-grace:
+			return s.streamErr
+		case <-deadline:
+			return s.streamErr
+		}
+	}
+}
+
+func (s *eventsStream) delivered() int {
+	return s.sent - s.failed
+}
+
+// failure accounts a failed event, gRPC status errors and EOF close the stream and are not events failures
+func (s *eventsStream) failure(err error) {
+	if _, ok := status.FromError(err); ok || err == io.EOF {
+		s.streamErr = err
+		return
+	}
+	s.failed++
+	utils.Printlnf("event failed: %s", err.Error())
+}
+
+// closedErr accounts the events left in the queue of a closed stream as failed
+func (s *eventsStream) closedErr() error {
+	for len(s.errCh) > 0 {
+		s.failure(<-s.errCh)
+	}
+	s.failed += len(s.eventsCh)
+	if s.streamErr != nil {
+		return fmt.Errorf("events stream closed, %s", s.streamErr.Error())
+	}
+	return fmt.Errorf("events stream closed")
+}
@@ -0,0 +1,247 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s/logs"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type EventsTailOptions struct {
+	cfg           *config.Config
+	transport     string
+	channels      []string
+	group         string
+	include       []string
+	exclude       []string
+	disableColor  bool
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	schemaOptions schema.Options
+	validators    map[string]*schema.Validator
+	inc           []*regexp.Regexp
+	exc           []*regexp.Regexp
+}
+
+var eventsTailExamples = `
+	# Tail all 'events' of channels 'orders' and 'billing' in one stream
+	kubemqctl events tail orders billing
+
+	# Tail all 'events' of channels matching wildcards, '*' matches one token and '>' matches one or more tokens
+	kubemqctl events tail 'orders.*' 'billing.>'
+
+	# Tail 'events' with metadata or body matching 'error' excluding 'healthcheck' events
+	kubemqctl events tail 'orders.>' --include error --exclude healthcheck
+
+	# Tail 'events' without colors
+	kubemqctl events tail 'orders.>' --disable-color
+`
+var eventsTailLong = `Tail command allows to subscribe to many 'events' channels or wildcard channels at once and merge their messages into one stream prefixed with the channel name`
+var eventsTailShort = `Tail messages of many 'events' channels command`
+
+func NewCmdEventsTail(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &EventsTailOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "tail",
+		Aliases: []string{"t"},
+		Short:   eventsTailShort,
+		Long:    eventsTailLong,
+		Example: eventsTailExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'events' channels consumer group (load balancing)")
+	cmd.PersistentFlags().StringArrayVarP(&o.include, "include", "i", []string{}, "set show only messages with metadata or body matching regex")
+	cmd.PersistentFlags().StringArrayVarP(&o.exclude, "exclude", "e", []string{}, "set hide messages with metadata or body matching regex")
+	cmd.PersistentFlags().BoolVarP(&o.disableColor, "disable-color", "", false, "Set to disable colorized output")
+	o.decodeOptions.AddFlags(cmd)
+	o.schemaOptions.AddFlags(cmd)
+	return cmd
+}
+
+func (o *EventsTailOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) == 0 {
+		return fmt.Errorf("missing channels arguments")
+	}
+	o.channels = args
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
+	}
+	if o.inc, err = compileRegexList(o.include); err != nil {
+		return fmt.Errorf("invalid include regex, %s", err.Error())
+	}
+	if o.exc, err = compileRegexList(o.exclude); err != nil {
+		return fmt.Errorf("invalid exclude regex, %s", err.Error())
+	}
+	if o.disableColor {
+		color.NoColor = true
+	}
+	// validators are resolved once per channel before subscribing, an explicit schema file is loaded once for all
+	// channels and channels of wildcard subscriptions are resolved on their first event
+	o.validators = map[string]*schema.Validator{}
+	if o.schemaOptions.Schema != "" {
+		if _, err := o.schemaOptions.Validator(o.cfg, ""); err != nil {
+			return err
+		}
+	}
+	for _, channel := range o.channels {
+		if strings.ContainsAny(channel, "*>") {
+			continue
+		}
+		v, err := o.schemaOptions.Validator(o.cfg, channel)
+		if err != nil {
+			return err
+		}
+		o.validators[channel] = v
+	}
+	return nil
+}
+
+func (o *EventsTailOptions) Validate() error {
+	return nil
+}
+
+func (o *EventsTailOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, len(o.channels))
+	merged := make(chan *kubemq2.Event, 100)
+	wg := sync.WaitGroup{}
+	for _, channel := range o.channels {
+		eventsChan, err := client.SubscribeToEvents(ctx, channel, o.group, errChan)
+		if err != nil {
+			return fmt.Errorf("receive 'events' messages from %s, %s", channel, err.Error())
+		}
+		wg.Add(1)
+		go func(eventsChan <-chan *kubemq2.Event) {
+			defer wg.Done()
+			for {
+				select {
+				case ev, opened := <-eventsChan:
+					if !opened {
+						return
+					}
+					select {
+					case merged <- ev:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(eventsChan)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	utils.Printlnf("waiting for 'events' messages from %s...", strings.Join(o.channels, ", "))
+	for {
+		select {
+		case ev, opened := <-merged:
+			if !opened {
+				utils.Println("server disconnected")
+				return nil
+			}
+			o.print(ev)
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (o *EventsTailOptions) print(event *kubemq2.Event) {
+	body := decoder.String(o.decoder, event.Body)
+	if !o.match(event.Metadata, body) {
+		return
+	}
+	schemaErr := schema.Error(o.validator(event.Channel), []byte(body))
+	body = strings.Replace(body, "\n", " ", -1)
+	prefix := logs.NameColor(event.Channel).SprintFunc()(fmt.Sprintf("[%s]", event.Channel))
+	if event.Metadata != "" {
+		body = fmt.Sprintf("{metadata: %s} %s", event.Metadata, body)
+	}
+	if schemaErr != "" {
+		body = fmt.Sprintf("%s %s", body, color.New(color.FgHiRed).Sprintf("[schema error: %s]", schemaErr))
+	}
+	fmt.Printf("%s %s\n", prefix, body)
+}
+
+// validator returns the validator of a channel, channels of wildcard subscriptions are known per event and are
+// resolved on their first event, a schema load error is reported once and the channel events are not validated
+func (o *EventsTailOptions) validator(channel string) *schema.Validator {
+	if v, ok := o.validators[channel]; ok {
+		return v
+	}
+	v, err := o.schemaOptions.Validator(o.cfg, channel)
+	if err != nil {
+		utils.Printlnf("channel %s schema not loaded, events are not validated, %s", channel, err.Error())
+	}
+	o.validators[channel] = v
+	return v
+}
+
+// match returns true when no exclude regex and at least one include regex, if any, match the metadata or body
+func (o *EventsTailOptions) match(metadata, body string) bool {
+	for _, rex := range o.exc {
+		if rex.MatchString(metadata) || rex.MatchString(body) {
+			return false
+		}
+	}
+	if len(o.inc) == 0 {
+		return true
+	}
+	for _, rin := range o.inc {
+		if rin.MatchString(metadata) || rin.MatchString(body) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileRegexList(list []string) ([]*regexp.Regexp, error) {
+	var rexList []*regexp.Regexp
+	for _, item := range list {
+		rex, err := regexp.Compile(item)
+		if err != nil {
+			return nil, err
+		}
+		rexList = append(rexList, rex)
+	}
+	return rexList, nil
+}
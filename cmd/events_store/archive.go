@@ -0,0 +1,33 @@
+package events_store
+
+import (
+	"time"
+
+	kubemq "github.com/kubemq-io/kubemq-go"
+)
+
+// eventsStoreArchiveRecord is a lossless representation of an events store event, body is kept as raw bytes
+// (base64 in json)
+type eventsStoreArchiveRecord struct {
+	Id        string            `json:"id"`
+	Channel   string            `json:"channel"`
+	ClientId  string            `json:"client_id,omitempty"`
+	Metadata  string            `json:"metadata,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Body      []byte            `json:"body,omitempty"`
+	Sequence  uint64            `json:"sequence"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+func newEventsStoreArchiveRecord(event *kubemq.EventStoreReceive) *eventsStoreArchiveRecord {
+	return &eventsStoreArchiveRecord{
+		Id:        event.Id,
+		Channel:   event.Channel,
+		ClientId:  event.ClientId,
+		Metadata:  event.Metadata,
+		Tags:      event.Tags,
+		Body:      event.Body,
+		Sequence:  event.Sequence,
+		Timestamp: event.Timestamp.UTC(),
+	}
+}
@@ -0,0 +1,93 @@
+package events_store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubemq "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+)
+
+// receiveBounds are the stop conditions of a bounded 'events store' subscription, zero values are not set
+type receiveBounds struct {
+	count         int
+	untilSequence uint64
+	until         time.Time
+	caughtUp      uint64
+	received      int
+}
+
+// withCaughtUp sets the caught up bound to the channel last sequence stored at the moment of the call, false is
+// returned when the channel has no events
+func (b *receiveBounds) withCaughtUp(ctx context.Context, cfg *config.Config, channel string) (bool, error) {
+	stores, err := getEventsStores(ctx, cfg)
+	if err != nil {
+		return false, fmt.Errorf("get events store channel last sequence, %s", err.Error())
+	}
+	ch := stores.channel(channel)
+	if ch == nil || ch.LastSequence == 0 {
+		return false, nil
+	}
+	b.caughtUp = uint64(ch.LastSequence)
+	return true, nil
+}
+
+// accept returns false for events sent after the until time, such events end the subscription without being handled
+func (b *receiveBounds) accept(event *kubemq.EventStoreReceive) bool {
+	return b.until.IsZero() || !event.Timestamp.After(b.until)
+}
+
+// done counts a handled event and returns the reason to stop when a bound is reached, empty reason otherwise
+func (b *receiveBounds) done(event *kubemq.EventStoreReceive) string {
+	b.received++
+	switch {
+	case b.count > 0 && b.received >= b.count:
+		return fmt.Sprintf("reached count of %d events", b.count)
+	case b.untilSequence > 0 && event.Sequence >= b.untilSequence:
+		return "reached until sequence"
+	case b.caughtUp > 0 && event.Sequence >= b.caughtUp:
+		return "caught up with last sequence"
+	}
+	return ""
+}
+
+// idleTimer resets on every event and fires when no event arrives during timeout, a zero timeout never fires
+type idleTimer struct {
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func newIdleTimer(timeout time.Duration) *idleTimer {
+	t := &idleTimer{timeout: timeout}
+	if timeout > 0 {
+		t.timer = time.NewTimer(timeout)
+	}
+	return t
+}
+
+func (t *idleTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		return nil
+	}
+	return t.timer.C
+}
+
+func (t *idleTimer) reset() {
+	if t.timer == nil {
+		return
+	}
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+	t.timer.Reset(t.timeout)
+}
+
+func (t *idleTimer) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
@@ -0,0 +1,195 @@
+package events_store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/archive"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type EventsStoreDumpOptions struct {
+	cfg           *config.Config
+	transport     string
+	channel       string
+	fileName      string
+	startSequence int
+	startTime     string
+	startDuration string
+	untilSequence uint64
+	untilTime     string
+	idleTimeout   time.Duration
+	subOptions    kubemq2.SubscriptionOption
+	until         time.Time
+}
+
+var eventsStoreDumpExamples = `
+	# Dump all events of 'events store' channel 'orders' into orders.ndjson
+	kubemqctl events_store dump orders
+
+	# Dump events of 'orders' channel from sequence 1000 up to sequence 2000 into a gzip file
+	kubemqctl events_store dump orders -f orders-1000-2000.ndjson.gz --start-sequence 1000 --until-sequence 2000
+
+	# Dump events of 'orders' channel of the last 24 hours to stdout
+	kubemqctl events_store dump orders -f - --start-duration 24h
+
+	# Dump events of 'orders' channel sent until a point in time (UTC)
+	kubemqctl events_store dump orders --until-time '2026-10-01 00:00:00'
+`
+var eventsStoreDumpLong = `Dump command allows to archive events of an 'events store' channel into a ndjson (.ndjson), gzip (.gz) or tar.gz (.tar.gz) file.
+Each record includes the event sequence and timestamp. Dump stops at the until bounds, or when caught up with the channel last sequence at start`
+var eventsStoreDumpShort = `Dump 'events store' channel events to a file command`
+
+func NewCmdEventsStoreDump(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &EventsStoreDumpOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "dump",
+		Aliases: []string{"d", "archive"},
+		Short:   eventsStoreDumpShort,
+		Long:    eventsStoreDumpLong,
+		Example: eventsStoreDumpExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.fileName, "file", "f", "", "set dump file name, .gz and .tar.gz are compressed, - writes to stdout (default <channel>.ndjson)")
+	cmd.PersistentFlags().IntVar(&o.startSequence, "start-sequence", 0, "start from event sequence")
+	cmd.PersistentFlags().StringVar(&o.startTime, "start-time", "", "start from timestamp format 2006-01-02 15:04:05")
+	cmd.PersistentFlags().StringVar(&o.startDuration, "start-duration", "", "start from time duration i.e. 1h")
+	cmd.PersistentFlags().Uint64Var(&o.untilSequence, "until-sequence", 0, "set stop after event sequence")
+	cmd.PersistentFlags().StringVar(&o.untilTime, "until-time", "", "set stop at events sent after timestamp format 2006-01-02 15:04:05 (UTC)")
+	cmd.PersistentFlags().DurationVar(&o.idleTimeout, "idle-timeout", 10*time.Second, "set stop when no event arrives during timeout, 0 waits forever")
+	return cmd
+}
+
+func (o *EventsStoreDumpOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	if o.fileName == "" {
+		o.fileName = fmt.Sprintf("%s.ndjson", o.channel)
+	}
+	var err error
+	if o.subOptions, err = subscriptionOption(false, false, false, o.startSequence, o.startTime, o.startDuration); err != nil {
+		return err
+	}
+	if o.subOptions == nil {
+		o.subOptions = kubemq2.StartFromFirstEvent()
+	}
+	if o.untilTime != "" {
+		if o.until, err = time.Parse("2006-01-02 15:04:05", o.untilTime); err != nil {
+			return fmt.Errorf("until time format error, %s", err.Error())
+		}
+	}
+	return nil
+}
+
+func (o *EventsStoreDumpOptions) Validate() error {
+	if o.untilSequence > 0 && o.startSequence > 0 && uint64(o.startSequence) > o.untilSequence {
+		return fmt.Errorf("until-sequence must be greater than start-sequence")
+	}
+	if o.idleTimeout < 0 {
+		return fmt.Errorf("idle-timeout must be zero or positive")
+	}
+	return nil
+}
+
+func (o *EventsStoreDumpOptions) Run(ctx context.Context) error {
+	bounds := &receiveBounds{
+		untilSequence: o.untilSequence,
+		until:         o.until,
+	}
+	// without bounds the dump is a snapshot up to the last sequence stored when the dump starts
+	if o.untilSequence == 0 && o.until.IsZero() {
+		hasEvents, err := bounds.withCaughtUp(ctx, o.cfg, o.channel)
+		if err != nil {
+			return err
+		}
+		if !hasEvents {
+			return fmt.Errorf("events store channel %s has no events", o.channel)
+		}
+	}
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	w, err := archive.NewWriter(o.fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 1)
+	eventsChan, err := client.SubscribeToEventsStore(ctx, o.channel, "", errChan, o.subOptions)
+	if err != nil {
+		return fmt.Errorf("subscribe to 'events store' channel, %s", err.Error())
+	}
+	if o.fileName != "-" {
+		utils.Printlnf("dumping 'events store' channel %s to %s...", o.channel, o.fileName)
+	}
+	idle := newIdleTimer(o.idleTimeout)
+	defer idle.stop()
+	var last uint64
+	reason := ""
+	for reason == "" {
+		select {
+		case ev, opened := <-eventsChan:
+			if !opened {
+				reason = "server disconnected"
+				break
+			}
+			idle.reset()
+			if !bounds.accept(ev) {
+				reason = "reached until time"
+				break
+			}
+			if err := w.Write(newEventsStoreArchiveRecord(ev)); err != nil {
+				return err
+			}
+			last = ev.Sequence
+			if w.Count()%1000 == 0 {
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			reason = bounds.done(ev)
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case <-idle.C():
+			reason = fmt.Sprintf("no events for %s", o.idleTimeout)
+		case <-ctx.Done():
+			reason = "canceled"
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if o.fileName != "-" {
+		utils.Printlnf("dumped %d events to %s, last sequence %d (%s)", w.Count(), o.fileName, last, reason)
+	}
+	return nil
+}
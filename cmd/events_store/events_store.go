@@ -19,6 +19,15 @@ var eventsExamples = `
 
 	# Execute list of 'events store' channels command
  	kubemqctl events_store list
+
+	# Execute dump of 'events store' channel command
+	kubemqctl events_store dump
+
+	# Execute publish of 'events store' archive command
+	kubemqctl events_store publish
+
+	# Execute 'events store' clients lag command
+	kubemqctl events_store lag
 `
 var eventsLong = `Execute Kubemq 'events_store' Pub/Sub commands`
 var eventsShort = `Execute Kubemq 'events_store' Pub/Sub commands`
@@ -31,7 +40,7 @@ func NewCmdEventsStore(ctx context.Context, cfg *config.Config) *cobra.Command {
 		Short:     eventsLong,
 		Long:      eventsShort,
 		Example:   eventsExamples,
-		ValidArgs: []string{"send", "receive", "attach", "list"},
+		ValidArgs: []string{"send", "receive", "attach", "list", "dump", "publish", "lag"},
 		Run: func(cmd *cobra.Command, args []string) {
 			utils.CheckErr(cmd.Help())
 		},
@@ -40,6 +49,9 @@ func NewCmdEventsStore(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.AddCommand(NewCmdEventsStoreReceive(ctx, cfg))
 	cmd.AddCommand(NewCmdEventsStoreAttach(ctx, cfg))
 	cmd.AddCommand(NewCmdEventsStoreList(ctx, cfg))
+	cmd.AddCommand(NewCmdEventsStoreDump(ctx, cfg))
+	cmd.AddCommand(NewCmdEventsStorePublish(ctx, cfg))
+	cmd.AddCommand(NewCmdEventsStoreLag(ctx, cfg))
 
 	return cmd
 }
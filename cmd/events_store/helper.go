@@ -1,25 +1,29 @@
 package events_store
 
 import (
-	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	kubemq "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
+	"time"
 )
 
 type object struct {
-	Id         string            `json:"id"`
-	Channel    string            `json:"channel,omitempty"`
-	ClientId   string            `json:"client_id,omitempty"`
-	Metadata   string            `json:"metadata,omitempty"`
-	Timestamp  string            `json:"timestamp,omitempty"`
-	Sequence   uint64            `json:"sequence,omitempty"`
-	Tags       map[string]string `json:"tags,omitempty"`
-	BodyJson   json.RawMessage   `json:"body_json,omitempty"`
-	BodyString string            `json:"body_string,omitempty"`
+	Id          string            `json:"id"`
+	Channel     string            `json:"channel,omitempty"`
+	ClientId    string            `json:"client_id,omitempty"`
+	Metadata    string            `json:"metadata,omitempty"`
+	Timestamp   string            `json:"timestamp,omitempty"`
+	Sequence    uint64            `json:"sequence,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	BodyJson    json.RawMessage   `json:"body_json,omitempty"`
+	BodyString  string            `json:"body_string,omitempty"`
+	DecodeError string            `json:"decode_error,omitempty"`
+	SchemaError string            `json:"schema_error,omitempty"`
 }
 
-func newObjectWithEventReceive(event *kubemq.EventStoreReceive) *object {
+func newObjectWithEventReceive(event *kubemq.EventStoreReceive, dec decoder.Decoder) *object {
 	obj := &object{
 		Id:         event.Id,
 		Channel:    event.Channel,
@@ -31,20 +35,13 @@ func newObjectWithEventReceive(event *kubemq.EventStoreReceive) *object {
 		BodyJson:   json.RawMessage{},
 		BodyString: "",
 	}
-	var js json.RawMessage
-	if err := json.Unmarshal(event.Body, &js); err == nil {
-		obj.BodyJson = js
-	} else {
-		sDec, err := b64.StdEncoding.DecodeString(string(event.Body))
-		if err != nil {
-			obj.BodyString = string(event.Body)
-		} else {
-			obj.BodyString = string(sDec)
-		}
+	var err error
+	if obj.BodyJson, obj.BodyString, err = decoder.Body(dec, event.Body); err != nil {
+		obj.DecodeError = err.Error()
 	}
 	return obj
 }
-func newObjectWithEventStore(event *kubemq.EventStore) *object {
+func newObjectWithEventStore(event *kubemq.EventStore, dec decoder.Decoder) *object {
 	obj := &object{
 		Id:         event.Id,
 		Channel:    event.Channel,
@@ -55,28 +52,68 @@ func newObjectWithEventStore(event *kubemq.EventStore) *object {
 		BodyString: "",
 	}
 
-	var js json.RawMessage
-	if err := json.Unmarshal(event.Body, &js); err == nil {
-		obj.BodyJson = js
-	} else {
-		sDec, err := b64.StdEncoding.DecodeString(string(event.Body))
-		if err != nil {
-			obj.BodyString = string(event.Body)
-		} else {
-			obj.BodyString = string(sDec)
-		}
+	var err error
+	if obj.BodyJson, obj.BodyString, err = decoder.Body(dec, event.Body); err != nil {
+		obj.DecodeError = err.Error()
 	}
 	return obj
 }
 
+// withSchema validates the decoded body against the schema, a validation error is set as the object schema error
+func (o *object) withSchema(v *schema.Validator) *object {
+	body := []byte(o.BodyJson)
+	if len(body) == 0 {
+		body = []byte(o.BodyString)
+	}
+	o.SchemaError = schema.Error(v, body)
+	return o
+}
+
 func (o *object) String() string {
 	data, _ := json.MarshalIndent(o, "", "    ")
 	return string(data)
 }
 
-func printEventReceive(event *kubemq.EventStoreReceive) {
-	fmt.Println(newObjectWithEventReceive(event))
+// Line returns the object as a single line json
+func (o *object) Line() string {
+	data, _ := json.Marshal(o)
+	return string(data)
+}
+
+func printEventReceive(event *kubemq.EventStoreReceive, dec decoder.Decoder, v *schema.Validator) {
+	fmt.Println(newObjectWithEventReceive(event, dec).withSchema(v))
 }
 func printEventStore(event *kubemq.EventStore) {
-	fmt.Println(newObjectWithEventStore(event))
+	fmt.Println(newObjectWithEventStore(event, nil))
+}
+
+// subscriptionOption returns the subscription start option set by the start flags, nil is returned when no flag is set
+func subscriptionOption(startNew, startFirst, startLast bool, startSequence int, startTime, startDuration string) (kubemq.SubscriptionOption, error) {
+	if startNew {
+		return kubemq.StartFromNewEvents(), nil
+	}
+	if startFirst {
+		return kubemq.StartFromFirstEvent(), nil
+	}
+	if startLast {
+		return kubemq.StartFromLastEvent(), nil
+	}
+	if startSequence > 0 {
+		return kubemq.StartFromSequence(startSequence), nil
+	}
+	if startTime != "" {
+		t, err := time.Parse("2006-01-02 15:04:05", startTime)
+		if err != nil {
+			return nil, fmt.Errorf("start time format error, %s", err.Error())
+		}
+		return kubemq.StartFromTime(t.UTC()), nil
+	}
+	if startDuration != "" {
+		d, err := time.ParseDuration(startDuration)
+		if err != nil {
+			return nil, fmt.Errorf("start duration format error, %s", err.Error())
+		}
+		return kubemq.StartFromTimeDelta(d), nil
+	}
+	return nil, nil
 }
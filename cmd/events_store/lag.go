@@ -0,0 +1,292 @@
+package events_store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type EventsStoreLagOptions struct {
+	cfg       *config.Config
+	transport string
+	filter    string
+	watch     bool
+	interval  time.Duration
+	maxLag    int64
+	stalled   bool
+	once      bool
+	output    string
+}
+
+var eventsStoreLagExamples = `
+	# Get the lag of all 'events store' clients, worst lag first
+	kubemqctl events_store lag
+
+	# Get the lag of clients of 'orders' channels only
+	kubemqctl events_store lag -f orders
+
+	# Watch clients lag and highlight clients with more than 1000 events behind or stalled, refresh every 5 seconds
+	kubemqctl events_store lag --watch --max-lag 1000
+
+	# Check clients lag once and exit with non-zero code when any client is stalled or more than 1000 events behind
+	kubemqctl events_store lag --once --max-lag 1000
+
+	# Get clients lag in Prometheus text format
+	kubemqctl events_store lag -o prometheus
+`
+var eventsStoreLagLong = `Lag command allows to get the lag of 'events store' clients, the number of events between the channel last sequence and the last sequence sent to the client, sorted by the worst lag`
+var eventsStoreLagShort = `Get 'events store' clients lag command`
+
+func NewCmdEventsStoreLag(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &EventsStoreLagOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "lag",
+		Aliases: []string{"lg"},
+		Short:   eventsStoreLagShort,
+		Long:    eventsStoreLagLong,
+		Example: eventsStoreLagExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.filter, "filter", "f", "", "set filter for channel / client name")
+	cmd.PersistentFlags().BoolVarP(&o.watch, "watch", "", false, "set watch mode, redraw the lag table every interval")
+	cmd.PersistentFlags().DurationVarP(&o.interval, "interval", "", 5*time.Second, "set watch mode refresh interval")
+	cmd.PersistentFlags().Int64VarP(&o.maxLag, "max-lag", "", 0, "set alert on clients with lag above max-lag events, 0 disables the threshold")
+	cmd.PersistentFlags().BoolVarP(&o.stalled, "alert-stalled", "", true, "set alert on stalled clients")
+	cmd.PersistentFlags().BoolVarP(&o.once, "once", "", false, "set print lag once and exit with non-zero code when any alert is triggered")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "table", "set output format table|json|prometheus")
+	return cmd
+}
+
+func (o *EventsStoreLagOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	return nil
+}
+
+func (o *EventsStoreLagOptions) Validate() error {
+	switch o.output {
+	case "table", "json", "prometheus":
+	default:
+		return fmt.Errorf("invalid output format %s, output must be table, json or prometheus", o.output)
+	}
+	if o.watch && o.once {
+		return fmt.Errorf("watch and once flags cannot be set together")
+	}
+	if o.watch && o.output != "table" {
+		return fmt.Errorf("watch mode supports table output only")
+	}
+	if o.watch && o.interval < time.Second {
+		return fmt.Errorf("interval must be at least 1 second")
+	}
+	if o.maxLag < 0 {
+		return fmt.Errorf("max-lag must be zero or a positive number")
+	}
+	return nil
+}
+
+func (o *EventsStoreLagOptions) Run(ctx context.Context) error {
+	if o.watch {
+		return o.runWatch(ctx)
+	}
+	q, err := getEventsStores(ctx, o.cfg)
+	if err != nil {
+		return err
+	}
+	lags := o.clientsLag(q)
+	switch o.output {
+	case "json":
+		data, _ := json.MarshalIndent(lags, "", "    ")
+		fmt.Println(string(data))
+	case "prometheus":
+		fmt.Print(lags.prometheus())
+	default:
+		lags.printTab()
+	}
+	if o.once {
+		if alerted := lags.alerted(); alerted > 0 {
+			return fmt.Errorf("%d 'events store' clients lag alerts triggered", alerted)
+		}
+	}
+	return nil
+}
+
+func (o *EventsStoreLagOptions) runWatch(ctx context.Context) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		q, err := getEventsStores(ctx, o.cfg)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		lags := o.clientsLag(q)
+		// clear screen and move the cursor home before redrawing
+		fmt.Print("\033[H\033[2J")
+		utils.Printlnf("Every %s: events store clients lag (%s)\n", o.interval, time.Now().Format("2006-01-02 15:04:05"))
+		lags.printTab()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// clientLag is the progress of an events store client relative to its channel last sequence
+type clientLag struct {
+	Channel          string   `json:"channel"`
+	ClientId         string   `json:"client_id"`
+	Subscription     int      `json:"subscription"`
+	Active           bool     `json:"active"`
+	LastSequence     int64    `json:"last_sequence"`
+	LastSequenceSent int64    `json:"last_sequence_sent"`
+	Lag              int64    `json:"lag"`
+	Pending          int64    `json:"pending"`
+	IsStalled        bool     `json:"is_stalled"`
+	Alerts           []string `json:"alerts,omitempty"`
+}
+
+type clientsLag []*clientLag
+
+// clientsLag computes the lag of every client matching the filter, sorted by worst lag first. Subscriptions of a
+// channel with the same client id, i.e. clients without an id, are numbered in the server order so each one has its
+// own prometheus series
+func (o *EventsStoreLagOptions) clientsLag(q *Queues) clientsLag {
+	lags := clientsLag{}
+	for _, ch := range q.Queues {
+		subscriptions := map[string]int{}
+		for _, c := range ch.Clients {
+			if o.filter != "" && !strings.Contains(ch.Name, o.filter) && !strings.Contains(c.ClientId, o.filter) {
+				continue
+			}
+			cl := &clientLag{
+				Channel:          ch.Name,
+				ClientId:         c.ClientId,
+				Active:           c.Active,
+				LastSequence:     ch.LastSequence,
+				LastSequenceSent: c.LastSequenceSent,
+				Lag:              ch.LastSequence - c.LastSequenceSent,
+				Pending:          c.Pending,
+				IsStalled:        c.IsStalled,
+			}
+			if cl.ClientId == "" {
+				cl.ClientId = "N/A"
+			}
+			cl.Subscription = subscriptions[cl.ClientId]
+			subscriptions[cl.ClientId]++
+			if cl.Lag < 0 {
+				cl.Lag = 0
+			}
+			if o.stalled && cl.IsStalled {
+				cl.Alerts = append(cl.Alerts, "stalled")
+			}
+			if o.maxLag > 0 && cl.Lag > o.maxLag {
+				cl.Alerts = append(cl.Alerts, fmt.Sprintf("lag>%d", o.maxLag))
+			}
+			lags = append(lags, cl)
+		}
+	}
+	sort.SliceStable(lags, func(i, j int) bool {
+		if lags[i].Lag != lags[j].Lag {
+			return lags[i].Lag > lags[j].Lag
+		}
+		if lags[i].Channel != lags[j].Channel {
+			return lags[i].Channel < lags[j].Channel
+		}
+		if lags[i].ClientId != lags[j].ClientId {
+			return lags[i].ClientId < lags[j].ClientId
+		}
+		return lags[i].Subscription < lags[j].Subscription
+	})
+	return lags
+}
+
+func (lags clientsLag) alerted() int {
+	cnt := 0
+	for _, cl := range lags {
+		if len(cl.Alerts) > 0 {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// printTab prints the lag table, rows of alerted clients are highlighted
+func (lags clientsLag) printTab() {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "CLIENT_ID\tCHANNEL\tACTIVE\tLAST_SEQUENCE\tLAST_SENT\tLAG\tPENDING\tSTALLED\tALERTS")
+	highlight := map[int]bool{}
+	for i, cl := range lags {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%d\t%d\t%d\t%t\t%s\n", cl.ClientId, cl.Channel, cl.Active, cl.LastSequence, cl.LastSequenceSent, cl.Lag, cl.Pending, cl.IsStalled, strings.Join(cl.Alerts, ","))
+		if len(cl.Alerts) > 0 {
+			highlight[i+1] = true
+		}
+	}
+	fmt.Fprintf(w, "\nTOTAL CLIENTS:\t%d\n", len(lags))
+	fmt.Fprintf(w, "ALERTED CLIENTS:\t%d\n", lags.alerted())
+	w.Flush()
+	// rows are colored after alignment, escape codes would break the tabwriter columns width
+	red := color.New(color.FgHiRed, color.Bold).SprintFunc()
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if highlight[i] {
+			line = red(line)
+		}
+		fmt.Println(line)
+	}
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheus returns the clients lag in Prometheus text exposition format
+func (lags clientsLag) prometheus() string {
+	metrics := []struct {
+		name  string
+		help  string
+		value func(cl *clientLag) int64
+	}{
+		{"kubemq_events_store_client_lag", "Number of events between the channel last sequence and the last sequence sent to the client", func(cl *clientLag) int64 { return cl.Lag }},
+		{"kubemq_events_store_client_pending", "Number of events pending delivery to the client", func(cl *clientLag) int64 { return cl.Pending }},
+		{"kubemq_events_store_client_last_sequence_sent", "Last event sequence sent to the client", func(cl *clientLag) int64 { return cl.LastSequenceSent }},
+		{"kubemq_events_store_client_stalled", "Whether the client is stalled (1) or not (0)", func(cl *clientLag) int64 { return boolGauge(cl.IsStalled) }},
+		{"kubemq_events_store_client_active", "Whether the client is active (1) or not (0)", func(cl *clientLag) int64 { return boolGauge(cl.Active) }},
+	}
+	sb := &strings.Builder{}
+	for _, m := range metrics {
+		fmt.Fprintf(sb, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(sb, "# TYPE %s gauge\n", m.name)
+		for _, cl := range lags {
+			fmt.Fprintf(sb, "%s{channel=\"%s\",client_id=\"%s\",subscription=\"%d\"} %d\n", m.name, prometheusLabelEscaper.Replace(cl.Channel), prometheusLabelEscaper.Replace(cl.ClientId), cl.Subscription, m.value(cl))
+		}
+	}
+	return sb.String()
+}
+
+func boolGauge(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
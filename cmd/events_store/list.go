@@ -65,25 +65,43 @@ func (o *EventsStoreListOptions) Validate() error {
 }
 
 func (o *EventsStoreListOptions) Run(ctx context.Context) error {
+	q, err := getEventsStores(ctx, o.cfg)
+	if err != nil {
+		return err
+	}
+	q.printChannelsTab(o.filter)
+	q.printClientsTab(o.filter)
+	return nil
+}
+
+func getEventsStores(ctx context.Context, cfg *config.Config) (*Queues, error) {
 	resp := &Response{}
 	q := &Queues{}
 
-	r, err := resty.New().R().SetResult(resp).SetError(resp).Get(fmt.Sprintf("%s/v1/stats/events_stores", o.cfg.GetApiHttpURI()))
+	r, err := resty.New().R().SetContext(ctx).SetResult(resp).SetError(resp).Get(fmt.Sprintf("%s/v1/stats/events_stores", cfg.GetApiHttpURI()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !r.IsSuccess() {
-		return fmt.Errorf("not available in current Kubemq version, consider upgrade Kubemq version")
+		return nil, fmt.Errorf("not available in current Kubemq version, consider upgrade Kubemq version")
 	}
 	if resp.Error {
-		return fmt.Errorf(resp.ErrorString)
+		return nil, fmt.Errorf(resp.ErrorString)
 	}
 	err = json.Unmarshal(resp.Data, q)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return q, nil
+}
+
+// channel returns the stats of an events store channel, nil is returned when the channel does not exist
+func (q *Queues) channel(name string) *Queue {
+	for _, c := range q.Queues {
+		if c.Name == name {
+			return c
+		}
 	}
-	q.printChannelsTab(o.filter)
-	q.printClientsTab(o.filter)
 	return nil
 }
 
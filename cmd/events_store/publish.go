@@ -0,0 +1,349 @@
+package events_store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubemq-io/kubemqctl/pkg/archive"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type EventsStorePublishOptions struct {
+	cfg              *config.Config
+	transport        string
+	fromFile         string
+	channel          string
+	remapList        []string
+	preserveMetadata bool
+	preserveId       bool
+	rate             int
+	originalTiming   bool
+	speed            float64
+	maxGap           time.Duration
+	stateFile        string
+	resume           bool
+	remaps           []*channelRemap
+}
+
+// channelRemap renames channels matching a regex, the replacement can reference the regex groups, i.e. $1
+type channelRemap struct {
+	from *regexp.Regexp
+	to   string
+}
+
+var eventsStorePublishExamples = `
+	# Republish all events of an archive to their original channels
+	kubemqctl events_store publish --from-file orders.ndjson
+
+	# Republish all events of an archive to channel 'orders-restored'
+	kubemqctl events_store publish --from-file orders.ndjson.gz --channel orders-restored
+
+	# Republish events remapping 'prod.*' channels to 'staging.*' channels at 100 events per second
+	kubemqctl events_store publish --from-file archive.ndjson --remap '^prod\.(.*)$=staging.$1' --rate 100
+
+	# Republish events at the original inter-event timing, twice as fast
+	kubemqctl events_store publish --from-file orders.ndjson --original-timing --speed 2
+
+	# Resume an interrupted republish from the last successfully sent line
+	kubemqctl events_store publish --from-file orders.ndjson --resume
+`
+var eventsStorePublishLong = `Publish command allows to republish events from an archive created by 'events_store dump' in order, with channel remapping, rate limiting or original timing replay.
+The last successfully sent line is saved every second in a state file so an interrupted publish can be resumed, a resume after a crash may republish the events sent in the last second. The state file is removed when the publish completes`
+var eventsStorePublishShort = `Republish 'events store' events from an archive file command`
+
+func NewCmdEventsStorePublish(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &EventsStorePublishOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "publish",
+		Aliases: []string{"p", "pub", "restore"},
+		Short:   eventsStorePublishShort,
+		Long:    eventsStorePublishLong,
+		Example: eventsStorePublishExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.fromFile, "from-file", "f", "", "set archive file to publish from (.ndjson, .gz, .tar.gz)")
+	cmd.PersistentFlags().StringVarP(&o.channel, "channel", "c", "", "set publish all events to channel, default is the archived event channel")
+	cmd.PersistentFlags().StringArrayVarP(&o.remapList, "remap", "", []string{}, "set channel remap regex=replacement, i.e. '^prod\\.(.*)$=staging.$1'")
+	cmd.PersistentFlags().BoolVarP(&o.preserveMetadata, "preserve-metadata", "", true, "set keep archived event metadata and tags")
+	cmd.PersistentFlags().BoolVarP(&o.preserveId, "preserve-id", "", false, "set keep archived event id, otherwise a new id is set")
+	cmd.PersistentFlags().IntVarP(&o.rate, "rate", "r", 0, "set max events per second to publish, 0 is unlimited")
+	cmd.PersistentFlags().BoolVarP(&o.originalTiming, "original-timing", "", false, "set replay events at the archived inter-event timing")
+	cmd.PersistentFlags().Float64VarP(&o.speed, "speed", "", 1, "set original timing replay speed factor, i.e. 2 replays twice as fast")
+	cmd.PersistentFlags().DurationVarP(&o.maxGap, "max-gap", "", 0, "set max wait between events in original timing replay, 0 is unlimited")
+	cmd.PersistentFlags().StringVarP(&o.stateFile, "state-file", "", "", "set file for saving the last sent line (default <from-file>.state)")
+	cmd.PersistentFlags().BoolVarP(&o.resume, "resume", "", false, "set resume from the last sent line saved in state file")
+	return cmd
+}
+
+func (o *EventsStorePublishOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if o.fromFile == "" {
+		return fmt.Errorf("missing --from-file archive file")
+	}
+	if o.stateFile == "" && o.fromFile != "-" {
+		o.stateFile = o.fromFile + ".state"
+	}
+	for _, item := range o.remapList {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid remap %s, remap must have regex=replacement format", item)
+		}
+		rex, err := regexp.Compile(kv[0])
+		if err != nil {
+			return fmt.Errorf("invalid remap %s regex, %s", item, err.Error())
+		}
+		o.remaps = append(o.remaps, &channelRemap{from: rex, to: kv[1]})
+	}
+	return nil
+}
+
+func (o *EventsStorePublishOptions) Validate() error {
+	if o.channel != "" && len(o.remaps) > 0 {
+		return fmt.Errorf("channel and remap cannot be set together")
+	}
+	if o.rate < 0 {
+		return fmt.Errorf("rate must be zero or a positive number")
+	}
+	if o.rate > 0 && o.originalTiming {
+		return fmt.Errorf("rate and original-timing cannot be set together")
+	}
+	if o.speed <= 0 {
+		return fmt.Errorf("speed must be a positive number")
+	}
+	if o.resume && o.stateFile == "" {
+		return fmt.Errorf("resume requires a state file")
+	}
+	return nil
+}
+
+func (o *EventsStorePublishOptions) Run(ctx context.Context) error {
+	resumeLine := 0
+	if o.resume {
+		var err error
+		if resumeLine, err = o.readState(); err != nil {
+			return err
+		}
+		if resumeLine > 0 {
+			utils.Printlnf("resuming after line %d", resumeLine)
+		}
+	}
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	r, err := archive.NewReader(o.fromFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	var ticker *time.Ticker
+	if o.rate > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(o.rate))
+		defer ticker.Stop()
+	}
+	utils.Printlnf("publishing events from %s...", o.fromFile)
+	var prev time.Time
+	sent, skipped := 0, 0
+	// the state is saved every second and when publishing stops, a crash loses at most the last second of state
+	lastLine, savedLine, savedAt := resumeLine, resumeLine, time.Now()
+	// a completed publish removes the state file, the deferred save is only for publishes which stopped early
+	completed := false
+	defer func() {
+		if !completed && lastLine > savedLine {
+			if err := o.writeState(lastLine); err != nil {
+				utils.Printlnf("%s", err.Error())
+			}
+		}
+	}()
+	for {
+		rec := &eventsStoreArchiveRecord{}
+		err := r.Next(rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if r.Line() <= resumeLine {
+			skipped++
+			prev = rec.Timestamp
+			continue
+		}
+		if err := o.wait(ctx, ticker, prev, rec.Timestamp); err != nil {
+			return err
+		}
+		prev = rec.Timestamp
+		channel := o.targetChannel(rec.Channel)
+		if channel == "" {
+			return fmt.Errorf("line %d has no channel, use --channel to set a channel", r.Line())
+		}
+		event := client.ES().
+			SetChannel(channel).
+			SetId(uuid.New().String()).
+			SetBody(rec.Body)
+		if o.preserveId && rec.Id != "" {
+			event.SetId(rec.Id)
+		}
+		if o.preserveMetadata {
+			event.SetMetadata(rec.Metadata).SetTags(rec.Tags)
+		}
+		res, err := event.Send(ctx)
+		if err != nil {
+			return fmt.Errorf("publish line %d (sequence %d), %s, resume with --resume", r.Line(), rec.Sequence, err.Error())
+		}
+		if res.Err != nil {
+			return fmt.Errorf("publish line %d (sequence %d), %s, resume with --resume", r.Line(), rec.Sequence, res.Err.Error())
+		}
+		sent++
+		lastLine = r.Line()
+		if time.Since(savedAt) >= time.Second {
+			if err := o.writeState(lastLine); err != nil {
+				return err
+			}
+			savedLine, savedAt = lastLine, time.Now()
+		}
+		if sent%1000 == 0 {
+			utils.Printlnf("published %d events", sent)
+		}
+	}
+	utils.Printlnf("published %d events, %d skipped by resume", sent, skipped)
+	completed = true
+	return o.removeState()
+}
+
+func (o *EventsStorePublishOptions) targetChannel(channel string) string {
+	if o.channel != "" {
+		return o.channel
+	}
+	for _, remap := range o.remaps {
+		if remap.from.MatchString(channel) {
+			return remap.from.ReplaceAllString(channel, remap.to)
+		}
+	}
+	return channel
+}
+
+// wait holds the next event according to the rate limit or the original timing between events
+func (o *EventsStorePublishOptions) wait(ctx context.Context, ticker *time.Ticker, prev, next time.Time) error {
+	if ticker != nil {
+		select {
+		case <-ticker.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if !o.originalTiming || prev.IsZero() || !next.After(prev) {
+		return nil
+	}
+	gap := time.Duration(float64(next.Sub(prev)) / o.speed)
+	if o.maxGap > 0 && gap > o.maxGap {
+		gap = o.maxGap
+	}
+	select {
+	case <-time.After(gap):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// publishState is the state file content, the archive path guards against resuming another archive
+type publishState struct {
+	Archive string `json:"archive"`
+	Line    int    `json:"line"`
+}
+
+func (o *EventsStorePublishOptions) archivePath() string {
+	path, err := filepath.Abs(o.fromFile)
+	if err != nil {
+		return o.fromFile
+	}
+	return path
+}
+
+func (o *EventsStorePublishOptions) readState() (int, error) {
+	data, err := ioutil.ReadFile(o.stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read state file, %s", err.Error())
+	}
+	state := &publishState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return 0, fmt.Errorf("invalid state file %s, %s", o.stateFile, err.Error())
+	}
+	if state.Archive != o.archivePath() {
+		return 0, fmt.Errorf("state file %s was saved for archive %s, remove it or set --state-file", o.stateFile, state.Archive)
+	}
+	return state.Line, nil
+}
+
+// writeState replaces the state file with a renamed temp file so a crash never leaves a partial state file
+func (o *EventsStorePublishOptions) writeState(line int) error {
+	if o.stateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(&publishState{Archive: o.archivePath(), Line: line})
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(o.stateFile), filepath.Base(o.stateFile)+".tmp")
+	if err != nil {
+		return fmt.Errorf("write state file, %s", err.Error())
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), o.stateFile)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("write state file, %s", err.Error())
+	}
+	return nil
+}
+
+func (o *EventsStorePublishOptions) removeState() error {
+	if o.stateFile == "" {
+		return nil
+	}
+	if err := os.Remove(o.stateFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove state file, %s", err.Error())
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	kubemq2 "github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"strconv"
@@ -27,6 +29,17 @@ type EventsStoreReceiveOptions struct {
 	startDuration string
 	hasFlags      bool
 	subOptions    kubemq2.SubscriptionOption
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	count         int
+	untilSequence uint64
+	untilTime     string
+	caughtUp      bool
+	idleTimeout   time.Duration
+	output        string
+	until         time.Time
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var eventsReceiveExamples = `
@@ -35,6 +48,18 @@ var eventsReceiveExamples = `
 
 	# Receive messages from an 'events channel' with group(blocks until next body)
 	kubemqctl events_store receive some-channel -g G1
+
+	# Receive messages from an 'events store' channel with kubemq targets request body
+	kubemqctl events_store receive some-channel --decode targets
+
+	# Receive 10 messages from the first message of an 'events store' channel and exit
+	kubemqctl events_store receive some-channel --start-first --count 10
+
+	# Receive messages sent during the last hour until caught up with the channel, one json line per message
+	kubemqctl events_store receive some-channel --start-duration 1h --exit-when-caught-up -o ndjson
+
+	# Assert in a script that exactly 5 messages were sent since a point in time (UTC)
+	test $(kubemqctl events_store receive some-channel --start-time '2026-10-01 00:00:00' --exit-when-caught-up -o ndjson | wc -l) -eq 5
 `
 var eventsReceiveLong = `Receive (Subscribe) command allows to consume messages from an 'events store' with options to set offset parameters`
 var eventsReceiveShort = `Receive a messages from an 'events store'`
@@ -67,6 +92,14 @@ func NewCmdEventsStoreReceive(ctx context.Context, cfg *config.Config) *cobra.Co
 	cmd.PersistentFlags().IntVar(&o.startSequence, "start-sequence", 0, "start from body sequence")
 	cmd.PersistentFlags().StringVar(&o.startTime, "start-time", "", "start from timestamp format 2006-01-02 15:04:05")
 	cmd.PersistentFlags().StringVar(&o.startDuration, "start-duration", "", "start from time duration i.e. 1h")
+	cmd.PersistentFlags().IntVar(&o.count, "count", 0, "set stop after receiving count messages, 0 receives until ctrl-c")
+	cmd.PersistentFlags().Uint64Var(&o.untilSequence, "until-sequence", 0, "set stop after body sequence")
+	cmd.PersistentFlags().StringVar(&o.untilTime, "until-time", "", "set stop at messages sent after timestamp format 2006-01-02 15:04:05 (UTC)")
+	cmd.PersistentFlags().BoolVar(&o.caughtUp, "exit-when-caught-up", false, "set stop when reaching the channel last sequence at start, default start is the first body")
+	cmd.PersistentFlags().DurationVar(&o.idleTimeout, "idle-timeout", 0, "set stop when no body arrives during timeout, 0 waits forever (default 5s with --exit-when-caught-up)")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "json", "set output format json|ndjson, ndjson prints one message per line and nothing else")
+	o.decodeOptions.AddFlags(cmd)
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -77,40 +110,33 @@ func (o *EventsStoreReceiveOptions) Complete(args []string, transport string) er
 	} else {
 		return fmt.Errorf("missing channel argument")
 	}
-
-	if o.startNew {
-		o.subOptions = kubemq2.StartFromNewEvents()
-		return nil
-	}
-	if o.startFirst {
-		o.subOptions = kubemq2.StartFromFirstEvent()
-		return nil
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
 	}
-	if o.startLast {
-		o.subOptions = kubemq2.StartFromLastEvent()
-		return nil
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
 	}
-	if o.startSequence > 0 {
-		o.subOptions = kubemq2.StartFromSequence(o.startSequence)
-		return nil
+
+	if o.subOptions, err = subscriptionOption(o.startNew, o.startFirst, o.startLast, o.startSequence, o.startTime, o.startDuration); err != nil {
+		return err
 	}
-	if o.startTime != "" {
-		t, err := time.Parse("2006-01-02 15:04:05", o.startTime)
-		if err != nil {
-			return fmt.Errorf("start time format error, %s", err.Error())
+	if o.untilTime != "" {
+		if o.until, err = time.Parse("2006-01-02 15:04:05", o.untilTime); err != nil {
+			return fmt.Errorf("until time format error, %s", err.Error())
 		}
-		o.subOptions = kubemq2.StartFromTime(t.UTC())
+	}
+	if o.caughtUp && o.idleTimeout == 0 {
+		o.idleTimeout = 5 * time.Second
+	}
+	if o.subOptions != nil {
 		return nil
 	}
-	if o.startDuration != "" {
-		d, err := time.ParseDuration(o.startDuration)
-		if err != nil {
-			return fmt.Errorf("start duration format error, %s", err.Error())
-		}
-		o.subOptions = kubemq2.StartFromTimeDelta(d)
+	if o.caughtUp {
+		o.subOptions = kubemq2.StartFromFirstEvent()
 		return nil
 	}
-	err := o.promptOptions()
+	err = o.promptOptions()
 	if err != nil {
 		return err
 	}
@@ -118,10 +144,42 @@ func (o *EventsStoreReceiveOptions) Complete(args []string, transport string) er
 }
 
 func (o *EventsStoreReceiveOptions) Validate() error {
+	if o.output != "json" && o.output != "ndjson" {
+		return fmt.Errorf("invalid output format %s, output must be json or ndjson", o.output)
+	}
+	if o.count < 0 {
+		return fmt.Errorf("count must be zero or a positive number")
+	}
+	if o.idleTimeout < 0 {
+		return fmt.Errorf("idle-timeout must be zero or positive")
+	}
+	if o.caughtUp && o.startNew {
+		return fmt.Errorf("exit-when-caught-up cannot be set with start-new")
+	}
+	if o.untilSequence > 0 && o.startSequence > 0 && uint64(o.startSequence) > o.untilSequence {
+		return fmt.Errorf("until-sequence must be greater than start-sequence")
+	}
 	return nil
 }
 
 func (o *EventsStoreReceiveOptions) Run(ctx context.Context) error {
+	bounds := &receiveBounds{
+		count:         o.count,
+		untilSequence: o.untilSequence,
+		until:         o.until,
+	}
+	if o.caughtUp {
+		hasEvents, err := bounds.withCaughtUp(ctx, o.cfg, o.channel)
+		if err != nil {
+			return err
+		}
+		if !hasEvents {
+			if o.output != "ndjson" {
+				utils.Printlnf("received 0 messages (channel %s has no messages)", o.channel)
+			}
+			return nil
+		}
+	}
 	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
 	if err != nil {
 		return fmt.Errorf("create kubemq client, %s", err.Error())
@@ -130,30 +188,63 @@ func (o *EventsStoreReceiveOptions) Run(ctx context.Context) error {
 	defer func() {
 		client.Close()
 	}()
-
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	errChan := make(chan error, 1)
 	eventsChan, err := client.SubscribeToEventsStore(ctx, o.channel, o.group, errChan, o.subOptions)
 
 	if err != nil {
-		utils.Println(fmt.Errorf("receive 'events store' messages, %s", err.Error()).Error())
+		return fmt.Errorf("receive 'events store' messages, %s", err.Error())
 	}
-	utils.Println("waiting for 'events store' messages...")
-
-	for {
+	// ndjson output is consumed by scripts, only the messages are printed
+	ndjson := o.output == "ndjson"
+	if !ndjson {
+		utils.Println("waiting for 'events store' messages...")
+	}
+	idle := newIdleTimer(o.idleTimeout)
+	defer idle.stop()
+	// a future until time ends the subscription also when no more messages arrive
+	var untilReached <-chan time.Time
+	if !o.until.IsZero() && o.until.After(time.Now()) {
+		untilTimer := time.NewTimer(time.Until(o.until))
+		defer untilTimer.Stop()
+		untilReached = untilTimer.C
+	}
+	reason := ""
+	for reason == "" {
 		select {
 		case ev, opened := <-eventsChan:
 			if !opened {
-				utils.Println("server disconnected")
+				if !ndjson {
+					utils.Println("server disconnected")
+				}
 				return nil
 			}
-			printEventReceive(ev)
+			idle.reset()
+			if !bounds.accept(ev) {
+				reason = "reached until time"
+				break
+			}
+			if ndjson {
+				fmt.Println(newObjectWithEventReceive(ev, o.decoder).withSchema(o.validator).Line())
+			} else {
+				printEventReceive(ev, o.decoder, o.validator)
+			}
+			reason = bounds.done(ev)
 		case err := <-errChan:
 			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case <-idle.C():
+			reason = fmt.Sprintf("no messages for %s", o.idleTimeout)
+		case <-untilReached:
+			reason = "reached until time"
 		case <-ctx.Done():
 			return nil
 		}
 	}
-
+	if !ndjson {
+		utils.Printlnf("received %d messages (%s)", bounds.received, reason)
+	}
+	return nil
 }
 
 func (o *EventsStoreReceiveOptions) promptOptions() error {
@@ -6,8 +6,10 @@ import (
 	"github.com/google/uuid"
 	kubemq2 "github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/targets"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -15,15 +17,20 @@ import (
 )
 
 type EventsStoreSendOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	body      string
-	metadata  string
-	messages  int
-	isStream  bool
-	fileName  bool
-	build     bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	body          string
+	metadata      string
+	messages      int
+	isStream      bool
+	fileName      bool
+	build         bool
+	noTemplate    bool
+	schemaOptions schema.Options
+	stdin         bool
+	flushTimeout  time.Duration
+	message       *generator.Message
 }
 
 var eventsSendExamples = `
@@ -38,6 +45,18 @@ var eventsSendExamples = `
 
 	# Send 100 messages to an 'events store' channel in stream mode
 	kubemqctl events_store send some-channel some-body -m 100 -s
+
+	# Send a message to an 'events store' channel after validating the body against a JSON Schema
+	kubemqctl events_store send some-channel '{"id":"o-1"}' --schema order.schema.json
+
+	# Send 1000 messages to an 'events store' channel with templated body and metadata, functions are uuid, now, seq, randInt, randChoice, env and file
+	kubemqctl events_store send some-channel '{"id":"{{uuid}}","n":{{seq}},"at":"{{now}}"}' --metadata 'seq-{{seq}}' -m 1000
+
+	# Send a body containing literal {{ }} braces to an 'events store' channel without templating
+	kubemqctl events_store send some-channel 'Hello {{ .Name }}' --no-template
+
+	# Send each line of stdin as a message body to an 'events store' channel
+	tail -f app.log | kubemqctl events_store send logs -
 `
 var eventsSendLong = `Send command allows to send (publish) one or many messages to an 'events store' channel`
 var eventsSendShort = `Send messages to an 'events store' channel command`
@@ -65,8 +84,11 @@ func NewCmdEventsStoreSend(ctx context.Context, cfg *config.Config) *cobra.Comma
 	cmd.PersistentFlags().StringVarP(&o.metadata, "metadata", "", "", "set body metadata field")
 	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "m", 1, "set how many 'events store' messages to send")
 	cmd.PersistentFlags().BoolVarP(&o.isStream, "stream", "s", false, "set stream of all messages at once")
+	cmd.PersistentFlags().DurationVarP(&o.flushTimeout, "flush-timeout", "", 10*time.Second, "set how long to wait for the store result of each streamed message (stream and stdin modes)")
 	cmd.PersistentFlags().BoolVarP(&o.fileName, "file", "f", false, "set load body from file")
 	cmd.PersistentFlags().BoolVarP(&o.build, "build", "b", false, "build kubemq targets request")
+	cmd.PersistentFlags().BoolVarP(&o.noTemplate, "no-template", "", false, "set send body and metadata as is without rendering {{ }} template actions")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -95,6 +117,7 @@ func (o *EventsStoreSendOptions) Complete(args []string, transport string) error
 	} else {
 		if len(args) >= 2 {
 			o.body = args[1]
+			o.stdin = o.body == "-"
 		} else {
 			return fmt.Errorf("missing body argument")
 		}
@@ -103,7 +126,34 @@ func (o *EventsStoreSendOptions) Complete(args []string, transport string) error
 }
 
 func (o *EventsStoreSendOptions) Validate() error {
-	return nil
+	// stdin lines are validated one by one as they arrive
+	if o.stdin {
+		return nil
+	}
+	var err error
+	if o.message, err = generator.NewMessage(o.body, o.metadata, !o.noTemplate && !o.fileName && !o.build, !o.noTemplate); err != nil {
+		return err
+	}
+	// templated bodies are validated per message, the first message is validated before connecting
+	body, _, err := o.message.Render(1)
+	if err != nil {
+		return err
+	}
+	return o.schemaOptions.ValidateBody(o.cfg, o.channel, body)
+}
+
+// render returns the body and metadata of message seq, templated bodies are validated against the channel schema
+func (o *EventsStoreSendOptions) render(seq int) ([]byte, string, error) {
+	body, metadata, err := o.message.Render(seq)
+	if err != nil {
+		return nil, "", err
+	}
+	if !o.message.IsStatic() {
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, body); err != nil {
+			return nil, "", fmt.Errorf("event store %d, %s", seq, err.Error())
+		}
+	}
+	return body, metadata, nil
 }
 
 func (o *EventsStoreSendOptions) Run(ctx context.Context) error {
@@ -115,36 +165,73 @@ func (o *EventsStoreSendOptions) Run(ctx context.Context) error {
 	defer func() {
 		client.Close()
 	}()
-
+	if o.stdin {
+		return o.runStdin(ctx, client)
+	}
 	if o.isStream {
 		utils.Printlnf("Streaming %d events store messages...", o.messages)
-		eventsCh := make(chan *kubemq2.EventStore, 1000)
-		eventsResultsCh := make(chan *kubemq2.EventStoreResult, 1000)
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		eventsCh := make(chan *kubemq2.EventStore, 1)
+		eventsResultsCh := make(chan *kubemq2.EventStoreResult, 1)
 		errCh := make(chan error, 10)
 		fmt.Println("Sending Stream Events Store:")
-		go client.StreamEventsStore(ctx, eventsCh, eventsResultsCh, errCh)
+		go client.StreamEventsStore(streamCtx, eventsCh, eventsResultsCh, errCh)
 		startTime := time.Now()
+		sent, failed := 0, 0
+		summary := func() {
+			utils.Printlnf("%d events store messages streamed in %s, %d delivered, %d failed.", sent+failed, time.Since(startTime), sent, failed)
+		}
 		for i := 1; i <= o.messages; i++ {
+			body, metadata, err := o.render(i)
+			if err != nil {
+				summary()
+				return err
+			}
 			msg := client.ES().
 				SetChannel(o.channel).
 				SetId(uuid.New().String()).
-				SetBody([]byte(o.body)).
-				SetMetadata(o.metadata)
+				SetBody(body).
+				SetMetadata(metadata)
 			printEventStore(msg)
 			eventsCh <- msg
-			<-eventsResultsCh
+			// every message waits for its store result, the wait is bounded by the flush timeout
+			select {
+			case res := <-eventsResultsCh:
+				if res.Err != nil {
+					utils.Printlnf("event store %s failed: %s", res.Id, res.Err.Error())
+					failed++
+					continue
+				}
+				sent++
+			case err := <-errCh:
+				summary()
+				return fmt.Errorf("streaming 'events store' messages, %s", err.Error())
+			case <-time.After(o.flushTimeout):
+				summary()
+				return fmt.Errorf("streaming 'events store' messages, no result for message %d in %s", i, o.flushTimeout)
+			case <-ctx.Done():
+				summary()
+				return nil
+			}
+		}
+		summary()
+		if failed > 0 {
+			return fmt.Errorf("%d of %d 'events store' messages failed", failed, o.messages)
 		}
-		utils.Printlnf("%d events store messages streamed in %s.", o.messages, time.Since(startTime))
-		time.Sleep(2 * time.Second)
 	} else {
 		fmt.Println("Sending Events Store:")
 		for i := 1; i <= o.messages; i++ {
+			body, metadata, err := o.render(i)
+			if err != nil {
+				return err
+			}
 			msg := client.ES().
 				SetChannel(o.channel).
 				SetId(uuid.New().String()).
-				SetBody([]byte(o.body)).
-				SetMetadata(o.metadata)
-			_, err := msg.Send(ctx)
+				SetBody(body).
+				SetMetadata(metadata)
+			_, err = msg.Send(ctx)
 			if err != nil {
 				return fmt.Errorf("sending 'events store' body, %s", err.Error())
 			}
@@ -0,0 +1,80 @@
+package events_store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// runStdin publishes each stdin line as an 'events store' message body as it arrives, every message waits for its
+// store result and the counts are reported on EOF
+func (o *EventsStoreSendOptions) runStdin(ctx context.Context, client *kubemq2.Client) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	eventsCh := make(chan *kubemq2.EventStore, 1)
+	eventsResultsCh := make(chan *kubemq2.EventStoreResult, 1)
+	errCh := make(chan error, 10)
+	go client.StreamEventsStore(ctx, eventsCh, eventsResultsCh, errCh)
+	linesCh, readErrCh := utils.ReadLines(ctx, os.Stdin)
+	utils.Printlnf("streaming stdin lines to 'events store' channel %s...", o.channel)
+	sent, failed, skipped, line := 0, 0, 0, 0
+	summary := func() {
+		utils.Printlnf("Summary: %d messages sent, %d failed, %d invalid lines skipped", sent, failed, skipped)
+	}
+	for {
+		var body string
+		var opened bool
+		select {
+		case body, opened = <-linesCh:
+		case <-ctx.Done():
+			summary()
+			return nil
+		}
+		if !opened {
+			summary()
+			select {
+			case err := <-readErrCh:
+				return fmt.Errorf("read stdin, %s", err.Error())
+			default:
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d messages failed to send", failed)
+			}
+			return nil
+		}
+		line++
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, []byte(body)); err != nil {
+			utils.Printlnf("line %d skipped, %s", line, err.Error())
+			skipped++
+			continue
+		}
+		eventsCh <- client.ES().
+			SetChannel(o.channel).
+			SetId(uuid.New().String()).
+			SetBody([]byte(body)).
+			SetMetadata(o.metadata)
+		select {
+		case res := <-eventsResultsCh:
+			if res.Err != nil {
+				utils.Printlnf("line %d failed, %s", line, res.Err.Error())
+				failed++
+				continue
+			}
+			sent++
+		case err := <-errCh:
+			summary()
+			return fmt.Errorf("stream 'events store' messages, %s", err.Error())
+		case <-time.After(o.flushTimeout):
+			summary()
+			return fmt.Errorf("stream 'events store' messages, no result for line %d in %s", line, o.flushTimeout)
+		case <-ctx.Done():
+			summary()
+			return nil
+		}
+	}
+}
@@ -6,16 +6,18 @@ import (
 	"github.com/kubemq-io/kubemqctl/pkg/attach"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type QueriesAttachOptions struct {
-	cfg       *config.Config
-	transport string
-	include   []string
-	exclude   []string
-	resources []string
+	cfg           *config.Config
+	transport     string
+	include       []string
+	exclude       []string
+	resources     []string
+	schemaOptions schema.Options
 }
 
 var queriesAttachExamples = `
@@ -59,6 +61,7 @@ func NewCmdQueriesAttach(ctx context.Context, cfg *config.Config) *cobra.Command
 	}
 	cmd.PersistentFlags().StringArrayVarP(&o.include, "include", "i", []string{}, "set (regex) strings to include")
 	cmd.PersistentFlags().StringArrayVarP(&o.exclude, "exclude", "e", []string{}, "set (regex) strings to exclude")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -82,7 +85,7 @@ func (o *QueriesAttachOptions) Validate() error {
 }
 
 func (o *QueriesAttachOptions) Run(ctx context.Context) error {
-	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude)
+	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude, &o.schemaOptions)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,215 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/metrics"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesCacheStatsOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	timeFrame string
+	history   bool
+	output    string
+}
+
+var queriesCacheStatsExamples = `
+	# Get cache hits, misses and hit ratio of a 'queries' channel
+	kubemqctl queries cache-stats some-channel
+
+	# Get cache stats of all 'queries' channels matching a pattern
+	kubemqctl queries cache-stats 'orders.*'
+
+	# Get cache stats of a 'queries' channel over the last hour with the stats history
+	kubemqctl queries cache-stats some-channel --time-frame 1h --history
+
+	# Get cache stats of a 'queries' channel in json format
+	kubemqctl queries cache-stats some-channel -o json
+`
+var queriesCacheStatsLong = `Cache stats command allows to get the response cache hits, misses and hit ratio of 'queries' channels from the stats api, to tune the cache ttl of queries`
+var queriesCacheStatsShort = `Get 'queries' channels response cache stats command`
+
+type cacheStatsPoint struct {
+	Time      string  `json:"time"`
+	Node      string  `json:"node"`
+	Queries   int64   `json:"queries"`
+	CacheHits int64   `json:"cache_hits"`
+	CacheMiss int64   `json:"cache_miss"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+type cacheStats struct {
+	Channel     string             `json:"channel"`
+	Group       string             `json:"group,omitempty"`
+	Clients     int64              `json:"clients"`
+	Queries     int64              `json:"queries"`
+	Responses   int64              `json:"responses"`
+	CacheHits   int64              `json:"cache_hits"`
+	CacheMiss   int64              `json:"cache_miss"`
+	HitRatio    float64            `json:"hit_ratio"`
+	MeanLatency float64            `json:"mean_latency"`
+	Errors      int64              `json:"errors"`
+	History     []*cacheStatsPoint `json:"history,omitempty"`
+}
+
+func NewCmdQueriesCacheStats(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesCacheStatsOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "cache-stats",
+		Aliases: []string{"cs"},
+		Short:   queriesCacheStatsShort,
+		Long:    queriesCacheStatsLong,
+		Example: queriesCacheStatsExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.timeFrame, "time-frame", "", "", "set stats time frame, i.e. 1h, default is the server time frame")
+	cmd.PersistentFlags().BoolVarP(&o.history, "history", "", false, "set show the cache stats history of the time frame")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "table", "set output format table|json")
+	return cmd
+}
+
+func (o *QueriesCacheStatsOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *QueriesCacheStatsOptions) Validate() error {
+	switch o.output {
+	case "table", "json":
+	default:
+		return fmt.Errorf("invalid output format %s, output must be table or json", o.output)
+	}
+	if _, err := path.Match(o.channel, ""); err != nil {
+		return fmt.Errorf("invalid channel pattern %s, %s", o.channel, err.Error())
+	}
+	return nil
+}
+
+func (o *QueriesCacheStatsOptions) Run(ctx context.Context) error {
+	m, err := metrics.GetMetrics(ctx, o.cfg, metrics.KindRPC, metrics.SubKindQueries, o.timeFrame)
+	if err != nil {
+		return fmt.Errorf("get 'queries' stats, %s", err.Error())
+	}
+	stats := o.cacheStats(m)
+	if len(stats) == 0 {
+		return fmt.Errorf("no 'queries' channel stats found for %s", o.channel)
+	}
+	if o.output == "json" {
+		data, _ := json.MarshalIndent(stats, "", "    ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printCacheStats(stats)
+	return nil
+}
+
+func (o *QueriesCacheStatsOptions) match(channel string) bool {
+	ok, _ := path.Match(o.channel, channel)
+	return ok || channel == o.channel
+}
+
+func (o *QueriesCacheStatsOptions) cacheStats(m *metrics.Metric) []*cacheStats {
+	var list []*cacheStats
+	byChannel := map[string]*cacheStats{}
+	for _, c := range m.Data.Channels.Channels {
+		if !o.match(c.Channel) {
+			continue
+		}
+		s := &cacheStats{
+			Channel:     c.Channel,
+			Group:       c.Group,
+			Clients:     c.Clients,
+			Queries:     c.Queries,
+			Responses:   c.Responses,
+			CacheHits:   c.CacheHits,
+			CacheMiss:   c.CacheMiss,
+			HitRatio:    hitRatio(c.CacheHits, c.CacheMiss),
+			MeanLatency: c.MeanLatency,
+			Errors:      c.Errors,
+		}
+		list = append(list, s)
+		if _, ok := byChannel[c.Channel]; !ok {
+			byChannel[c.Channel] = s
+		}
+	}
+	if o.history {
+		for _, d := range m.Data.Charts.ChartsData {
+			s, ok := byChannel[d.Channel]
+			if !ok {
+				continue
+			}
+			s.History = append(s.History, &cacheStatsPoint{
+				Time:      d.Time.Format("2006-01-02 15:04:05"),
+				Node:      d.Node,
+				Queries:   d.Queries,
+				CacheHits: d.CacheHits,
+				CacheMiss: d.CacheMiss,
+				HitRatio:  hitRatio(d.CacheHits, d.CacheMiss),
+			})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Channel != list[j].Channel {
+			return list[i].Channel < list[j].Channel
+		}
+		return list[i].Group < list[j].Group
+	})
+	return list
+}
+
+// hitRatio returns the percent of queries served from cache
+func hitRatio(hits, miss int64) float64 {
+	if hits+miss == 0 {
+		return 0
+	}
+	return float64(hits) * 100 / float64(hits+miss)
+}
+
+func printCacheStats(stats []*cacheStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "CHANNEL\tGROUP\tCLIENTS\tQUERIES\tRESPONSES\tCACHE HITS\tCACHE MISS\tHIT RATIO\tMEAN LATENCY\tERRORS\n")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t%.2f\t%d\n", s.Channel, s.Group, s.Clients, s.Queries, s.Responses, s.CacheHits, s.CacheMiss, s.HitRatio, s.MeanLatency, s.Errors)
+	}
+	w.Flush()
+	for _, s := range stats {
+		if len(s.History) == 0 {
+			continue
+		}
+		fmt.Println()
+		utils.Printlnf("cache stats history of %s:", s.Channel)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
+		fmt.Fprintf(w, "TIME\tNODE\tQUERIES\tCACHE HITS\tCACHE MISS\tHIT RATIO\n")
+		for _, p := range s.History {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n", p.Time, p.Node, p.Queries, p.CacheHits, p.CacheMiss, p.HitRatio)
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,160 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/grouptest"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesGroupTestOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	group     string
+	members   int
+	messages  int
+	timeout   int
+}
+
+var queriesGroupTestExamples = `
+	# Check how 100 'queries' are distributed across 4 members of group G1 on channel 'some-channel'
+	kubemqctl queries group-test some-channel -g G1 --members 4
+
+	# Check distribution of 1000 'queries' across 8 members with 2 seconds query timeout
+	kubemqctl queries group-test some-channel -g G1 --members 8 --messages 1000 --timeout 2
+`
+var queriesGroupTestLong = `Group-test command allows to verify consumer group load balancing of a 'queries' channel. It subscribes N responders in the same group, sends test queries and reports how the queries were distributed across the members, and whether any query was duplicated or lost`
+var queriesGroupTestShort = `Test 'queries' consumer group load balancing command`
+
+func NewCmdQueriesGroupTest(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesGroupTestOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "group-test",
+		Aliases: []string{"gt"},
+		Short:   queriesGroupTestShort,
+		Long:    queriesGroupTestLong,
+		Example: queriesGroupTestExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'queries' channel consumer group to test")
+	cmd.PersistentFlags().IntVarP(&o.members, "members", "m", 3, "set number of group members to subscribe")
+	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "n", 100, "set number of test queries to send")
+	cmd.PersistentFlags().IntVarP(&o.timeout, "timeout", "o", 5, "set query timeout in seconds")
+	return cmd
+}
+
+func (o *QueriesGroupTestOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+		return nil
+	}
+	return fmt.Errorf("missing channel argument")
+}
+
+func (o *QueriesGroupTestOptions) Validate() error {
+	if o.group == "" {
+		return fmt.Errorf("missing group, set group with -g")
+	}
+	if o.members <= 0 || o.messages <= 0 {
+		return fmt.Errorf("members and messages must be positive numbers")
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("timeout must be a positive number")
+	}
+	return nil
+}
+
+func (o *QueriesGroupTestOptions) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracker := grouptest.NewTracker(o.members)
+	errChan := make(chan error, o.members)
+	for i := 0; i < o.members; i++ {
+		client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+		if err != nil {
+			return fmt.Errorf("create kubemq client, %s", err.Error())
+		}
+		defer func(client *kubemq2.Client) {
+			client.Close()
+		}(client)
+		queriesChan, err := client.SubscribeToQueries(ctx, o.channel, o.group, errChan)
+		if err != nil {
+			return fmt.Errorf("subscribe member %d, %s", i+1, err.Error())
+		}
+		go func(member int, client *kubemq2.Client, queriesChan <-chan *kubemq2.QueryReceive) {
+			for {
+				select {
+				case query, opened := <-queriesChan:
+					if !opened {
+						return
+					}
+					if !tracker.Received(member, query.Id) {
+						continue
+					}
+					_ = client.R().
+						SetRequestId(query.Id).
+						SetResponseTo(query.ResponseTo).
+						SetExecutedAt(time.Now()).
+						SetBody([]byte(fmt.Sprintf("executed by member %d", member+1))).
+						Send(ctx)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i, client, queriesChan)
+	}
+	// subscriptions are registered by the server asynchronously, queries sent before are not delivered
+	time.Sleep(time.Second)
+
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	utils.Printlnf("sending %d 'queries' to channel %s, group %s with %d members...", o.messages, o.channel, o.group, o.members)
+	for i := 0; i < o.messages; i++ {
+		select {
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		default:
+		}
+		// failed queries are reported as lost
+		_, _ = client.Q().
+			SetId(tracker.NextId()).
+			SetChannel(o.channel).
+			SetMetadata("kubemqctl group-test").
+			SetBody([]byte(fmt.Sprintf("group-test query %d", i+1))).
+			SetTimeout(time.Duration(o.timeout) * time.Second).
+			Send(ctx)
+	}
+	report := tracker.Report()
+	report.Print(os.Stdout)
+	if !report.Ok() {
+		return fmt.Errorf("group-test failed, %d queries duplicated, %d queries lost", report.Duplicated, report.Lost)
+	}
+	utils.Println("group-test passed, each query was delivered to exactly one member")
+	return nil
+}
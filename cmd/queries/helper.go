@@ -6,22 +6,24 @@ import (
 	"fmt"
 
 	kubemq "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"strconv"
 )
 
 type object struct {
-	Id         string            `json:"id"`
-	Channel    string            `json:"channel,omitempty"`
-	ClientId   string            `json:"client_id,omitempty"`
-	Metadata   string            `json:"metadata,omitempty"`
-	Tags       map[string]string `json:"tags,omitempty"`
-	Timeout    string            `json:"timeout,omitempty"`
-	BodyJson   json.RawMessage   `json:"body_json,omitempty"`
-	BodyString string            `json:"body_string,omitempty"`
-	Executed   string            `json:"executed,omitempty"`
-	ExecutedAt string            `json:"executed_at,omitempty"`
-	Error      string            `json:"error,omitempty"`
-	CacheHit   string            `json:"cache_hit,omitempty"`
+	Id          string            `json:"id"`
+	Channel     string            `json:"channel,omitempty"`
+	ClientId    string            `json:"client_id,omitempty"`
+	Metadata    string            `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Timeout     string            `json:"timeout,omitempty"`
+	BodyJson    json.RawMessage   `json:"body_json,omitempty"`
+	BodyString  string            `json:"body_string,omitempty"`
+	Executed    string            `json:"executed,omitempty"`
+	ExecutedAt  string            `json:"executed_at,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	CacheHit    string            `json:"cache_hit,omitempty"`
+	SchemaError string            `json:"schema_error,omitempty"`
 }
 
 func newObjectWithQueryReceive(query *kubemq.QueryReceive) *object {
@@ -115,8 +117,10 @@ func (o *object) String() string {
 	return string(data)
 }
 
-func printQueryReceive(query *kubemq.QueryReceive) {
-	fmt.Println(newObjectWithQueryReceive(query))
+func printQueryReceive(query *kubemq.QueryReceive, v *schema.Validator) {
+	obj := newObjectWithQueryReceive(query)
+	obj.SchemaError = schema.Error(v, query.Body)
+	fmt.Println(obj)
 }
 
 func printQueryResponse(response *kubemq.QueryResponse) {
@@ -0,0 +1,106 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/probe"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesProbeOptions struct {
+	cfg          *config.Config
+	transport    string
+	channel      string
+	probeOptions probe.Options
+}
+
+var queriesProbeExamples = `
+	# Probe a 'queries' channel responder every second and print rolling latency percentiles and error rate
+	kubemqctl queries probe some-channel
+
+	# Send 60 probe queries and exit with non-zero code when the p99 latency is not below 200ms or the error rate not below 1%
+	kubemqctl queries probe some-channel -n 60 --slo-p99 200ms --slo-error-rate 1
+
+	# Probe continuously over a 5 minutes window and exit with non-zero code as soon as the SLO is breached
+	kubemqctl queries probe some-channel -w 5m --slo-p95 100ms --exit-on-breach
+`
+var queriesProbeLong = `Probe command allows to send a query to a 'queries' channel every interval and report the rolling round-trip latency percentiles and error rate, checking them against an SLO`
+var queriesProbeShort = `Probe a 'queries' channel responder latency and SLO command`
+
+func NewCmdQueriesProbe(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesProbeOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "probe",
+		Aliases: []string{"p"},
+		Short:   queriesProbeShort,
+		Long:    queriesProbeLong,
+		Example: queriesProbeExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	o.probeOptions.AddFlags(cmd)
+	return cmd
+}
+
+func (o *QueriesProbeOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *QueriesProbeOptions) Validate() error {
+	return o.probeOptions.Validate()
+}
+
+func (o *QueriesProbeOptions) Run(ctx context.Context) error {
+	// an interrupt ends the probe run with the summary and the final SLO check
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	utils.Printlnf("probing 'queries' channel %s every %s...", o.channel, o.probeOptions.Interval)
+	return probe.Run(ctx, &o.probeOptions, os.Stdout, func(ctx context.Context, timeout time.Duration) error {
+		res, err := client.Q().
+			SetChannel(o.channel).
+			SetId(uuid.New().String()).
+			SetBody([]byte(o.probeOptions.Body)).
+			SetMetadata(o.probeOptions.Metadata).
+			SetTimeout(timeout).
+			Send(ctx)
+		if err != nil {
+			return err
+		}
+		if !res.Executed {
+			return fmt.Errorf("query not executed, %s", res.Error)
+		}
+		return nil
+	})
+}
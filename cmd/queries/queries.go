@@ -17,6 +17,27 @@ var queriesExamples = `
 	# Execute attach to 'queries' command
 	kubemqctl queries attach
 
+	# Execute test 'queries' consumer group load balancing command
+	kubemqctl queries group-test
+
+	# Execute mock 'queries' responder from a rules file command
+	kubemqctl queries mock
+
+	# Execute serve 'queries' with a process or http endpoint command
+	kubemqctl queries serve
+
+	# Execute get 'queries' channels response cache stats command
+	kubemqctl queries cache-stats
+
+	# Execute probe 'queries' channel responder latency and SLO command
+	kubemqctl queries probe
+
+	# Execute record 'queries' channel requests and responses command
+	kubemqctl queries record
+
+	# Execute replay recorded 'queries' requests and diff responses command
+	kubemqctl queries replay
+
 `
 var queriesLong = `Execute Kubemq 'queries' RPC based commands`
 var queriesShort = `Execute Kubemq 'queries' RPC based commands`
@@ -30,7 +51,7 @@ func NewCmdQueries(ctx context.Context, cfg *config.Config) *cobra.Command {
 		Short:     queriesShort,
 		Long:      queriesLong,
 		Example:   queriesExamples,
-		ValidArgs: []string{"send", "receive", "attach"},
+		ValidArgs: []string{"send", "receive", "attach", "group-test", "mock", "serve", "cache-stats", "probe", "record", "replay"},
 		Run: func(cmd *cobra.Command, args []string) {
 			utils.CheckErr(cmd.Help())
 		},
@@ -38,6 +59,13 @@ func NewCmdQueries(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.AddCommand(NewCmdQueriesSend(ctx, cfg))
 	cmd.AddCommand(NewCmdQueriesReceive(ctx, cfg))
 	cmd.AddCommand(NewCmdQueriesAttach(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesGroupTest(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesMock(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesServe(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesCacheStats(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesProbe(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesRecord(ctx, cfg))
+	cmd.AddCommand(NewCmdQueriesReplay(ctx, cfg))
 
 	return cmd
 }
@@ -0,0 +1,158 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kubemq-io/kubemqctl/pkg/attach"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/session"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesRecordOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	out       string
+	wait      time.Duration
+	duration  time.Duration
+}
+
+var queriesRecordExamples = `
+	# Record 'queries' channel request/response pairs to a session file until interrupted
+	kubemqctl queries record some-channel --out session.ndjson
+
+	# Record requests without timeout waiting up to 1 minute for their response
+	kubemqctl queries record some-channel --out session.ndjson --wait 1m
+
+	# Record for 10 minutes and exit
+	kubemqctl queries record some-channel --out session.ndjson --duration 10m
+`
+var queriesRecordLong = `Record command allows to attach to a 'queries' channel as an observer and save each request and its response as a line of an ndjson session file, requests without a response are saved without one`
+var queriesRecordShort = `Record 'queries' channel requests and responses command`
+
+func NewCmdQueriesRecord(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesRecordOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "record",
+		Aliases: []string{"rec"},
+		Short:   queriesRecordShort,
+		Long:    queriesRecordLong,
+		Example: queriesRecordExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.out, "out", "o", "", "set session file to write the recorded requests and responses to")
+	cmd.PersistentFlags().DurationVarP(&o.wait, "wait", "w", 30*time.Second, "set how long to wait for the response of requests without timeout")
+	cmd.PersistentFlags().DurationVarP(&o.duration, "duration", "d", 0, "set how long to record, 0 records until interrupted")
+	return cmd
+}
+
+func (o *QueriesRecordOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *QueriesRecordOptions) Validate() error {
+	if o.out == "" {
+		return fmt.Errorf("missing session file, set --out flag")
+	}
+	if o.wait <= 0 {
+		return fmt.Errorf("wait must be a positive duration")
+	}
+	if o.duration < 0 {
+		return fmt.Errorf("duration must be zero or a positive duration")
+	}
+	return nil
+}
+
+func (o *QueriesRecordOptions) Run(ctx context.Context) error {
+	// stop recording on interrupt or after duration, requests waiting for a response are saved before exiting
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if o.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.duration)
+		defer cancel()
+	}
+	f, err := os.Create(o.out)
+	if err != nil {
+		return fmt.Errorf("create session file, %s", err.Error())
+	}
+	defer f.Close()
+	recorder := session.NewRecorder(o.wait)
+	recorded, unanswered := 0, 0
+	write := func(ex *session.Exchange) error {
+		if ex.Response == nil {
+			unanswered++
+			utils.Printlnf("request %s: no response seen", ex.RequestId)
+		} else {
+			recorded++
+			utils.Printlnf("request %s: recorded response in %.2fms", ex.RequestId, ex.LatencyMs)
+		}
+		if err := session.Write(f, ex); err != nil {
+			return fmt.Errorf("write session file, %s", err.Error())
+		}
+		return nil
+	}
+
+	msgCh := attach.Subscribe(ctx, o.cfg, "queries", o.channel)
+	if o.duration > 0 {
+		utils.Printlnf("recording 'queries' channel %s to %s for %s, press Ctrl-C to stop...", o.channel, o.out, o.duration)
+	} else {
+		utils.Printlnf("recording 'queries' channel %s to %s, press Ctrl-C to stop...", o.channel, o.out)
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+OUTER:
+	for {
+		select {
+		case msg, ok := <-msgCh:
+			if !ok {
+				break OUTER
+			}
+			if ex := recorder.Add(o.channel, msg, time.Now()); ex != nil {
+				if err := write(ex); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			for _, ex := range recorder.Expire(time.Now()) {
+				if err := write(ex); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, ex := range recorder.Flush() {
+		if err := write(ex); err != nil {
+			return err
+		}
+	}
+	utils.Printlnf("Summary: %d requests recorded with response, %d without response, session file %s", recorded, unanswered, o.out)
+	if ctx.Err() == nil {
+		return fmt.Errorf("attach connection closed, recording stopped")
+	}
+	return nil
+}
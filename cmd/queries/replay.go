@@ -0,0 +1,162 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/session"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesReplayOptions struct {
+	cfg       *config.Config
+	transport string
+	file      string
+	channel   string
+	timeout   time.Duration
+	compare   []string
+	exchanges []*session.Exchange
+}
+
+var queriesReplayExamples = `
+	# Replay the requests of a recorded session file and compare the new responses with the recorded ones
+	kubemqctl queries replay session.ndjson
+
+	# Replay the recorded requests to another 'queries' channel
+	kubemqctl queries replay session.ndjson --channel some-staging-channel
+
+	# Replay comparing only the responses executed status and body
+	kubemqctl queries replay session.ndjson --compare executed,body
+`
+var queriesReplayLong = `Replay command allows to re-send the requests of a session file recorded by 'queries record' and compare each new response with the recorded one, exits with non-zero code when any response differs or a request fails`
+var queriesReplayShort = `Replay recorded 'queries' requests and diff responses command`
+
+func NewCmdQueriesReplay(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesReplayOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "replay",
+		Aliases: []string{"rep"},
+		Short:   queriesReplayShort,
+		Long:    queriesReplayLong,
+		Example: queriesReplayExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.channel, "channel", "c", "", "set 'queries' channel to replay to instead of the recorded channel")
+	cmd.PersistentFlags().DurationVarP(&o.timeout, "timeout", "t", 0, "set replayed query timeout instead of the recorded timeout")
+	cmd.PersistentFlags().StringSliceVarP(&o.compare, "compare", "", session.Fields, "set response fields to compare, "+strings.Join(session.Fields, ", "))
+	return cmd
+}
+
+func (o *QueriesReplayOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.file = args[0]
+	} else {
+		return fmt.Errorf("missing session file argument")
+	}
+	return nil
+}
+
+func (o *QueriesReplayOptions) Validate() error {
+	if o.timeout < 0 {
+		return fmt.Errorf("timeout must be a positive duration")
+	}
+	if err := session.ValidateFields(o.compare); err != nil {
+		return err
+	}
+	var err error
+	o.exchanges, err = session.Read(o.file)
+	if err != nil {
+		return err
+	}
+	if len(o.exchanges) == 0 {
+		return fmt.Errorf("session file %s has no recorded requests", o.file)
+	}
+	return nil
+}
+
+func (o *QueriesReplayOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+	}
+	defer func() {
+		client.Close()
+	}()
+	alert := color.New(color.FgHiRed).SprintFunc()
+	matched, differed, skipped, failed := 0, 0, 0, 0
+	for i, ex := range o.exchanges {
+		if ctx.Err() != nil {
+			break
+		}
+		channel := ex.Channel
+		if o.channel != "" {
+			channel = o.channel
+		}
+		timeout := o.timeout
+		if timeout == 0 {
+			timeout = time.Duration(ex.TimeoutMs) * time.Millisecond
+		}
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		res, err := client.Q().
+			SetChannel(channel).
+			SetId(uuid.New().String()).
+			SetBody(ex.Body).
+			SetMetadata(ex.Metadata).
+			SetTags(ex.Tags).
+			SetTimeout(timeout).
+			Send(ctx)
+		if err != nil {
+			failed++
+			utils.Printlnf("[%d] request %s: %s", i+1, ex.RequestId, alert(fmt.Sprintf("error: %s", err.Error())))
+			continue
+		}
+		if ex.Response == nil {
+			skipped++
+			utils.Printlnf("[%d] request %s: replayed, no recorded response to compare", i+1, ex.RequestId)
+			continue
+		}
+		diffs := session.Diff(ex.Response, &session.Response{
+			Executed: res.Executed,
+			Error:    res.Error,
+			Metadata: res.Metadata,
+			Body:     res.Body,
+			Tags:     res.Tags,
+		}, o.compare)
+		if len(diffs) == 0 {
+			matched++
+			utils.Printlnf("[%d] request %s: match", i+1, ex.RequestId)
+			continue
+		}
+		differed++
+		utils.Printlnf("[%d] request %s: %s", i+1, ex.RequestId, alert("diff"))
+		for _, diff := range diffs {
+			utils.Printlnf("\t%s", diff)
+		}
+	}
+	utils.Printlnf("Summary: %d requests replayed, %d matched, %d differed, %d without recorded response, %d failed", matched+differed+skipped+failed, matched, differed, skipped, failed)
+	if differed > 0 || failed > 0 {
+		return fmt.Errorf("replay failed, %d responses differed, %d requests failed", differed, failed)
+	}
+	return nil
+}
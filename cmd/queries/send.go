@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/targets"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -14,16 +16,18 @@ import (
 )
 
 type QueriesSendOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	body      string
-	metadata  string
-	timeout   int
-	cacheKey  string
-	cacheTTL  time.Duration
-	fileName  bool
-	build     bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	body          string
+	metadata      string
+	timeout       int
+	cacheKey      string
+	cacheTTL      time.Duration
+	fileName      bool
+	build         bool
+	noTemplate    bool
+	schemaOptions schema.Options
 }
 
 var queriesSendExamples = `
@@ -36,8 +40,17 @@ var queriesSendExamples = `
 	# Send query to a 'queries' channel with 120 seconds timeout
 	kubemqctl queries send some-channel some-body -o 120
 	
-	# Send query to a 'queries' channel with cache-key and cache duration of 1m
-	kubemqctl queries send some-channel some-body -c cache-key -d 1m
+	# Send query to a 'queries' channel with cache-key and cache ttl of 1m, the output shows whether the response was served from cache
+	kubemqctl queries send some-channel some-body --cache-key cache-key --cache-ttl 1m
+
+	# Send query to a 'queries' channel after validating the body against a JSON Schema
+	kubemqctl queries send some-channel '{"id":"o-1"}' --schema query.schema.json
+
+	# Send query with templated body, functions are uuid, now, seq, randInt, randChoice, env and file
+	kubemqctl queries send some-channel '{"id":"{{uuid}}","at":"{{now}}"}'
+
+	# Send query with a body containing literal {{ }} braces without templating
+	kubemqctl queries send some-channel 'Hello {{ .Name }}' --no-template
 `
 var queriesSendLong = `Send command allow to send messages to 'queries' channel with an option to set query time-out and caching parameters`
 var queriesSendShort = `Send messages to a 'queries' channel command`
@@ -65,9 +78,13 @@ func NewCmdQueriesSend(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&o.metadata, "metadata", "m", "", "set query body metadata field")
 	cmd.PersistentFlags().StringVarP(&o.cacheKey, "cache-key", "c", "", "set query cache key")
 	cmd.PersistentFlags().IntVarP(&o.timeout, "timeout", "o", 30, "set query timeout")
-	cmd.PersistentFlags().DurationVarP(&o.cacheTTL, "cache-duration", "d", 10*time.Minute, "set cache duration timeout")
+	cmd.PersistentFlags().DurationVarP(&o.cacheTTL, "cache-ttl", "d", 10*time.Minute, "set how long the response of a query with cache key is cached")
+	cmd.PersistentFlags().DurationVarP(&o.cacheTTL, "cache-duration", "", 10*time.Minute, "set cache duration timeout")
+	_ = cmd.PersistentFlags().MarkDeprecated("cache-duration", "use --cache-ttl instead")
 	cmd.PersistentFlags().BoolVarP(&o.fileName, "file", "f", false, "set load body from file")
 	cmd.PersistentFlags().BoolVarP(&o.build, "build", "b", false, "build kubemq targets request")
+	cmd.PersistentFlags().BoolVarP(&o.noTemplate, "no-template", "", false, "set send body and metadata as is without rendering {{ }} template actions")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -103,7 +120,19 @@ func (o *QueriesSendOptions) Complete(args []string, transport string) error {
 	return nil
 }
 func (o *QueriesSendOptions) Validate() error {
-	return nil
+	if o.cacheKey != "" && o.cacheTTL <= 0 {
+		return fmt.Errorf("cache ttl must be a positive duration when cache key is set")
+	}
+	message, err := generator.NewMessage(o.body, o.metadata, !o.noTemplate && !o.fileName && !o.build, !o.noTemplate)
+	if err != nil {
+		return err
+	}
+	body, metadata, err := message.Render(1)
+	if err != nil {
+		return err
+	}
+	o.body, o.metadata = string(body), metadata
+	return o.schemaOptions.ValidateBody(o.cfg, o.channel, body)
 }
 
 func (o *QueriesSendOptions) Run(ctx context.Context) error {
@@ -131,5 +160,13 @@ func (o *QueriesSendOptions) Run(ctx context.Context) error {
 	}
 	fmt.Println("Getting Query Response:")
 	printQueryResponse(res)
+	// only executed responses are cached
+	switch {
+	case o.cacheKey == "":
+	case res.CacheHit:
+		utils.Printlnf("response served from cache, cache key: %s", o.cacheKey)
+	case res.Executed:
+		utils.Printlnf("response executed by responder and cached for %s, cache key: %s", o.cacheTTL, o.cacheKey)
+	}
 	return nil
 }
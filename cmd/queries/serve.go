@@ -0,0 +1,162 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/handler"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueriesServeOptions struct {
+	cfg            *config.Config
+	transport      string
+	channel        string
+	group          string
+	handlerOptions handler.Options
+	handler        handler.Handler
+	mu             sync.Mutex
+}
+
+var queriesServeExamples = `
+	# Serve 'queries' of a 'queries' channel with a process, each query is written as json to the process stdin
+	# and the process prints the response json, i.e. {"body":"{\"status\":\"shipped\"}"} or {"error":"not found"}, to its stdout.
+	# Bodies which are not valid UTF-8 are passed and returned base64 encoded in the body_base64 field
+	kubemqctl queries serve some-channel --exec ./handler
+
+	# Serve 'queries' of a 'queries' channel with an http endpoint, each query json is posted to the endpoint
+	kubemqctl queries serve some-channel --http http://localhost:8081/handle
+
+	# Serve 'queries' with group, handling up to 50 queries at once with 5 seconds timeout each
+	kubemqctl queries serve some-channel -g G1 --http http://localhost:8081/handle -c 50 -t 5s
+`
+var queriesServeLong = `Serve command allows to answer the 'queries' of a channel with an external process or http endpoint, handled queries are sent executed responses with the handler body and failed or timed out queries are sent error responses`
+var queriesServeShort = `Serve a 'queries' channel with a process or http endpoint command`
+
+func NewCmdQueriesServe(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueriesServeOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "serve",
+		Aliases: []string{"sv"},
+		Short:   queriesServeShort,
+		Long:    queriesServeLong,
+		Example: queriesServeExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.group, "group", "g", "", "set 'queries' channel consumer group (load balancing)")
+	o.handlerOptions.AddFlags(cmd)
+	return cmd
+}
+
+func (o *QueriesServeOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	return nil
+}
+
+func (o *QueriesServeOptions) Validate() error {
+	var err error
+	o.handler, err = o.handlerOptions.Handler()
+	return err
+}
+
+func (o *QueriesServeOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	errChan := make(chan error, 1)
+	queriesChan, err := client.SubscribeToQueries(ctx, o.channel, o.group, errChan)
+	if err != nil {
+		return fmt.Errorf("receive 'queries' messages, %s", err.Error())
+	}
+	utils.Printlnf("serving 'queries' of %s with %s...", o.channel, o.handler)
+	// the semaphore bounds the queries handled at once, the next query is received when a handler is free
+	sem := make(chan struct{}, o.handlerOptions.Concurrency)
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+	for {
+		select {
+		case err := <-errChan:
+			return fmt.Errorf("server disconnected with error: %s", err.Error())
+		case query, opened := <-queriesChan:
+			if !opened {
+				utils.Println("server disconnected")
+				return nil
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			wg.Add(1)
+			go func(query *kubemq2.QueryReceive) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				o.serve(ctx, client, query)
+			}(query)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (o *QueriesServeOptions) serve(ctx context.Context, client *kubemq2.Client, query *kubemq2.QueryReceive) {
+	handlerCtx, cancel := context.WithTimeout(ctx, o.handlerOptions.Timeout)
+	defer cancel()
+	start := time.Now()
+	req := &handler.Request{
+		Id:       query.Id,
+		Type:     "query",
+		Channel:  query.Channel,
+		ClientId: query.ClientId,
+		Metadata: query.Metadata,
+		Tags:     query.Tags,
+	}
+	res, err := o.handler.Handle(handlerCtx, req.SetBody(query.Body))
+	if err != nil {
+		res = &handler.Response{
+			Error: err.Error(),
+		}
+	}
+	sendErr := res.Apply(client.R().SetRequestId(query.Id).SetResponseTo(query.ResponseTo)).Send(ctx)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	result := "executed"
+	if res.Error != "" {
+		result = fmt.Sprintf("error: %s", res.Error)
+	}
+	if sendErr != nil {
+		utils.Printlnf("query %s: %s in %s, send response failed, %s", query.Id, result, time.Since(start), sendErr.Error())
+		return
+	}
+	utils.Printlnf("query %s: %s in %s", query.Id, result, time.Since(start))
+}
@@ -0,0 +1,84 @@
+package queue
+
+import (
+	kubemq "github.com/kubemq-io/kubemq-go"
+)
+
+type queueArchivePolicy struct {
+	ExpirationSeconds int32  `json:"expiration_seconds,omitempty"`
+	DelaySeconds      int32  `json:"delay_seconds,omitempty"`
+	MaxReceiveCount   int32  `json:"max_receive_count,omitempty"`
+	MaxReceiveQueue   string `json:"max_receive_queue,omitempty"`
+}
+
+type queueArchiveAttributes struct {
+	Timestamp         int64  `json:"timestamp,omitempty"`
+	Sequence          uint64 `json:"sequence,omitempty"`
+	MD5OfBody         string `json:"md5_of_body,omitempty"`
+	ReceiveCount      int32  `json:"receive_count,omitempty"`
+	ReRouted          bool   `json:"re_routed,omitempty"`
+	ReRoutedFromQueue string `json:"re_routed_from_queue,omitempty"`
+	ExpirationAt      int64  `json:"expiration_at,omitempty"`
+	DelayedTo         int64  `json:"delayed_to,omitempty"`
+}
+
+// queueArchiveRecord is a lossless representation of a queue message, body is kept as raw bytes (base64 in json)
+type queueArchiveRecord struct {
+	Id         string                  `json:"id"`
+	Channel    string                  `json:"channel"`
+	ClientId   string                  `json:"client_id,omitempty"`
+	Metadata   string                  `json:"metadata,omitempty"`
+	Tags       map[string]string       `json:"tags,omitempty"`
+	Body       []byte                  `json:"body,omitempty"`
+	Policy     *queueArchivePolicy     `json:"policy,omitempty"`
+	Attributes *queueArchiveAttributes `json:"attributes,omitempty"`
+}
+
+func newQueueArchiveRecord(msg *kubemq.QueueMessage) *queueArchiveRecord {
+	rec := &queueArchiveRecord{
+		Id:       msg.MessageID,
+		Channel:  msg.Channel,
+		ClientId: msg.ClientID,
+		Metadata: msg.Metadata,
+		Tags:     msg.Tags,
+		Body:     msg.Body,
+	}
+	if msg.Policy != nil {
+		rec.Policy = &queueArchivePolicy{
+			ExpirationSeconds: msg.Policy.ExpirationSeconds,
+			DelaySeconds:      msg.Policy.DelaySeconds,
+			MaxReceiveCount:   msg.Policy.MaxReceiveCount,
+			MaxReceiveQueue:   msg.Policy.MaxReceiveQueue,
+		}
+	}
+	if msg.Attributes != nil {
+		rec.Attributes = &queueArchiveAttributes{
+			Timestamp:         msg.Attributes.Timestamp,
+			Sequence:          msg.Attributes.Sequence,
+			MD5OfBody:         msg.Attributes.MD5OfBody,
+			ReceiveCount:      msg.Attributes.ReceiveCount,
+			ReRouted:          msg.Attributes.ReRouted,
+			ReRoutedFromQueue: msg.Attributes.ReRoutedFromQueue,
+			ExpirationAt:      msg.Attributes.ExpirationAt,
+			DelayedTo:         msg.Attributes.DelayedTo,
+		}
+	}
+	return rec
+}
+
+// toQueueMessage builds a new queue message for channel from the record, keeping id, metadata, tags, body and policy
+func (r *queueArchiveRecord) toQueueMessage(client *kubemq.Client, channel string) *kubemq.QueueMessage {
+	msg := client.QM().
+		SetId(r.Id).
+		SetChannel(channel).
+		SetMetadata(r.Metadata).
+		SetTags(r.Tags).
+		SetBody(r.Body)
+	if r.Policy != nil {
+		msg.SetPolicyExpirationSeconds(int(r.Policy.ExpirationSeconds)).
+			SetPolicyDelaySeconds(int(r.Policy.DelaySeconds)).
+			SetPolicyMaxReceiveCount(int(r.Policy.MaxReceiveCount)).
+			SetPolicyMaxReceiveQueue(r.Policy.MaxReceiveQueue)
+	}
+	return msg
+}
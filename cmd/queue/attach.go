@@ -8,16 +8,18 @@ import (
 	"github.com/kubemq-io/kubemqctl/pkg/attach"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type QueueAttachOptions struct {
-	cfg       *config.Config
-	transport string
-	include   []string
-	exclude   []string
-	resources []string
+	cfg           *config.Config
+	transport     string
+	include       []string
+	exclude       []string
+	resources     []string
+	schemaOptions schema.Options
 }
 
 var queueAttachExamples = `
@@ -61,6 +63,7 @@ func NewCmdQueueAttach(ctx context.Context, cfg *config.Config) *cobra.Command {
 	}
 	cmd.PersistentFlags().StringArrayVarP(&o.include, "include", "i", []string{}, "aet (regex) strings to include")
 	cmd.PersistentFlags().StringArrayVarP(&o.exclude, "exclude", "e", []string{}, "set (regex) strings to exclude")
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -110,7 +113,7 @@ func (o *QueueAttachOptions) Validate() error {
 }
 
 func (o *QueueAttachOptions) Run(ctx context.Context) error {
-	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude)
+	err := attach.Run(ctx, o.cfg, o.resources, o.include, o.exclude, &o.schemaOptions)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,340 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/latency"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const benchTimestampTag = "kubemqctl-bench-ts"
+
+type QueueBenchOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	producers int
+	consumers int
+	size      int
+	rate      int
+	batchSize int
+	duration  time.Duration
+	wait      int
+	output    string
+}
+
+var queueBenchExamples = `
+	# Run a 10 seconds benchmark with 1 producer and 1 consumer of 1KB messages on 'bench' queue
+	kubemqctl queue bench bench
+
+	# Run a 1 minute benchmark with 4 producers and 4 consumers sending batches of 100 messages of 4KB
+	kubemqctl queue bench bench --producers 4 --consumers 4 --batch-size 100 --size 4096 --duration 1m
+
+	# Run a benchmark limited to 500 messages per second and print the report as json
+	kubemqctl queue bench bench --rate 500 --output json
+`
+var queueBenchLong = `Bench command allows to load a queue channel with producers and consumers and report throughput, end-to-end latency percentiles and errors. Use a dedicated channel, the benchmark consumes all messages in the channel`
+var queueBenchShort = `Benchmark a queue channel command`
+
+type queueBenchReport struct {
+	Channel       string          `json:"channel"`
+	Producers     int             `json:"producers"`
+	Consumers     int             `json:"consumers"`
+	MessageSize   int             `json:"message_size"`
+	BatchSize     int             `json:"batch_size"`
+	Rate          int             `json:"rate"`
+	Duration      float64         `json:"duration_seconds"`
+	Sent          int64           `json:"sent"`
+	SendErrors    int64           `json:"send_errors"`
+	Received      int64           `json:"received"`
+	ReceiveErrors int64           `json:"receive_errors"`
+	Lost          int64           `json:"lost"`
+	SendRate      float64         `json:"send_rate"`
+	ReceiveRate   float64         `json:"receive_rate"`
+	SendMBps      float64         `json:"send_mbps"`
+	SendLatency   *latency.Millis `json:"send_latency"`
+	Latency       *latency.Millis `json:"end_to_end_latency"`
+}
+
+type queueBench struct {
+	sent          int64
+	sendErrors    int64
+	received      int64
+	receiveErrors int64
+	producersDone int32
+	sendLatency   *latency.Recorder
+	e2eLatency    *latency.Recorder
+}
+
+func NewCmdQueueBench(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueBenchOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "bench",
+		Aliases: []string{"b", "benchmark"},
+		Short:   queueBenchShort,
+		Long:    queueBenchLong,
+		Example: queueBenchExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().IntVarP(&o.producers, "producers", "p", 1, "set number of concurrent producers")
+	cmd.PersistentFlags().IntVarP(&o.consumers, "consumers", "c", 1, "set number of concurrent consumers")
+	cmd.PersistentFlags().IntVarP(&o.size, "size", "s", 1024, "set message body size in bytes")
+	cmd.PersistentFlags().IntVarP(&o.rate, "rate", "r", 0, "set max messages per second sent by all producers, 0 is unlimited")
+	cmd.PersistentFlags().IntVarP(&o.batchSize, "batch-size", "b", 1, "set how many messages are sent and received per request")
+	cmd.PersistentFlags().DurationVarP(&o.duration, "duration", "d", 10*time.Second, "set how long producers send messages")
+	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds consumers wait for messages after producers are done")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "table", "set report output format, table or json")
+	return cmd
+}
+
+func (o *QueueBenchOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+		return nil
+	}
+	return fmt.Errorf("missing channel argument")
+}
+
+func (o *QueueBenchOptions) Validate() error {
+	if o.producers <= 0 || o.consumers <= 0 {
+		return fmt.Errorf("producers and consumers must be positive numbers")
+	}
+	if o.size <= 0 || o.batchSize <= 0 {
+		return fmt.Errorf("size and batch-size must be positive numbers")
+	}
+	if o.rate < 0 {
+		return fmt.Errorf("rate must be zero or a positive number")
+	}
+	if o.duration <= 0 || o.wait <= 0 {
+		return fmt.Errorf("duration and wait must be positive")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %s, output must be table or json", o.output)
+	}
+	return nil
+}
+
+func (o *QueueBenchOptions) Run(ctx context.Context) error {
+	body := make([]byte, o.size)
+	if _, err := rand.Read(body); err != nil {
+		return err
+	}
+	var clients []*kubemq2.Client
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	for i := 0; i < o.producers+o.consumers; i++ {
+		client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+		if err != nil {
+			return fmt.Errorf("create kubemq client, %s", err.Error())
+		}
+		clients = append(clients, client)
+	}
+	b := &queueBench{
+		sendLatency: latency.NewRecorder(),
+		e2eLatency:  latency.NewRecorder(),
+	}
+	if o.output == "table" {
+		utils.Printlnf("running benchmark on queue %s for %s with %d producers and %d consumers...", o.channel, o.duration, o.producers, o.consumers)
+	}
+	start := time.Now()
+	producersCtx, cancel := context.WithTimeout(ctx, o.duration)
+	defer cancel()
+	producers := sync.WaitGroup{}
+	for i := 0; i < o.producers; i++ {
+		producers.Add(1)
+		go func(client *kubemq2.Client) {
+			defer producers.Done()
+			o.produce(producersCtx, client, b, body)
+		}(clients[i])
+	}
+	consumers := sync.WaitGroup{}
+	for i := 0; i < o.consumers; i++ {
+		consumers.Add(1)
+		go func(client *kubemq2.Client) {
+			defer consumers.Done()
+			o.consume(ctx, client, b)
+		}(clients[o.producers+i])
+	}
+	producers.Wait()
+	sendElapsed := time.Since(start)
+	atomic.StoreInt32(&b.producersDone, 1)
+	consumers.Wait()
+	return o.report(b, sendElapsed, time.Since(start))
+}
+
+func (o *QueueBenchOptions) produce(ctx context.Context, client *kubemq2.Client, b *queueBench, body []byte) {
+	var ticker *time.Ticker
+	if o.rate > 0 {
+		// the total rate is shared by all producers, each tick sends a batch
+		interval := time.Duration(float64(time.Second) * float64(o.producers*o.batchSize) / float64(o.rate))
+		ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+	}
+	for ctx.Err() == nil {
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+		batch := make([]*kubemq2.QueueMessage, 0, o.batchSize)
+		for i := 0; i < o.batchSize; i++ {
+			batch = append(batch, client.QM().
+				SetChannel(o.channel).
+				SetBody(body).
+				AddTag(benchTimestampTag, strconv.FormatInt(time.Now().UnixNano(), 10)))
+		}
+		sendStart := time.Now()
+		results, err := client.SendQueueMessages(ctx, batch)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			atomic.AddInt64(&b.sendErrors, int64(len(batch)))
+			continue
+		}
+		b.sendLatency.Add(time.Since(sendStart))
+		for _, res := range results {
+			if res.IsError {
+				atomic.AddInt64(&b.sendErrors, 1)
+			} else {
+				atomic.AddInt64(&b.sent, 1)
+			}
+		}
+	}
+}
+
+// consume receives messages until producers are done and all sent messages were received or no message arrived
+// during the wait time. Receive errors back off from 100ms up to 2s before retrying
+func (o *QueueBenchOptions) consume(ctx context.Context, client *kubemq2.Client, b *queueBench) {
+	backoff := time.Duration(0)
+	for ctx.Err() == nil {
+		done := atomic.LoadInt32(&b.producersDone) == 1
+		if done && atomic.LoadInt64(&b.received) >= atomic.LoadInt64(&b.sent) {
+			return
+		}
+		wait := 1
+		if done {
+			wait = o.wait
+		}
+		res, err := client.RQM().
+			SetChannel(o.channel).
+			SetMaxNumberOfMessages(o.batchSize).
+			SetWaitTimeSeconds(wait).
+			Send(ctx)
+		if err != nil || res.IsError {
+			if ctx.Err() != nil {
+				return
+			}
+			atomic.AddInt64(&b.receiveErrors, 1)
+			if done {
+				return
+			}
+			backoff *= 2
+			if backoff == 0 {
+				backoff = 100 * time.Millisecond
+			}
+			if backoff > 2*time.Second {
+				backoff = 2 * time.Second
+			}
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		backoff = 0
+		now := time.Now()
+		for _, msg := range res.Messages {
+			ts, err := strconv.ParseInt(msg.Tags[benchTimestampTag], 10, 64)
+			if err != nil {
+				// not a benchmark message
+				continue
+			}
+			atomic.AddInt64(&b.received, 1)
+			b.e2eLatency.Add(now.Sub(time.Unix(0, ts)))
+		}
+		if len(res.Messages) == 0 && done {
+			return
+		}
+	}
+}
+
+func (o *QueueBenchOptions) report(b *queueBench, sendElapsed, elapsed time.Duration) error {
+	r := &queueBenchReport{
+		Channel:       o.channel,
+		Producers:     o.producers,
+		Consumers:     o.consumers,
+		MessageSize:   o.size,
+		BatchSize:     o.batchSize,
+		Rate:          o.rate,
+		Duration:      elapsed.Seconds(),
+		Sent:          b.sent,
+		SendErrors:    b.sendErrors,
+		Received:      b.received,
+		ReceiveErrors: b.receiveErrors,
+		SendRate:      float64(b.sent) / sendElapsed.Seconds(),
+		ReceiveRate:   float64(b.received) / elapsed.Seconds(),
+		SendMBps:      float64(b.sent) * float64(o.size) / sendElapsed.Seconds() / 1024 / 1024,
+		SendLatency:   b.sendLatency.Summary().Millis(),
+		Latency:       b.e2eLatency.Summary().Millis(),
+	}
+	if r.Sent > r.Received {
+		r.Lost = r.Sent - r.Received
+	}
+	if o.output == "json" {
+		data, err := json.MarshalIndent(r, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "\nRESULTS:\n")
+	fmt.Fprintf(w, "DURATION:\t%.2fs\n", r.Duration)
+	fmt.Fprintf(w, "SENT:\t%d\t(%.1f msgs/sec, %.2f MB/sec)\n", r.Sent, r.SendRate, r.SendMBps)
+	fmt.Fprintf(w, "RECEIVED:\t%d\t(%.1f msgs/sec)\n", r.Received, r.ReceiveRate)
+	fmt.Fprintf(w, "SEND ERRORS:\t%d\n", r.SendErrors)
+	fmt.Fprintf(w, "RECEIVE ERRORS:\t%d\n", r.ReceiveErrors)
+	fmt.Fprintf(w, "NOT RECEIVED:\t%d\n", r.Lost)
+	fmt.Fprintf(w, "\nLATENCY (ms):\tMIN\tMEAN\tP50\tP95\tP99\tMAX\n")
+	for _, l := range []struct {
+		name string
+		ms   *latency.Millis
+	}{{"SEND", r.SendLatency}, {"END-TO-END", r.Latency}} {
+		fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\n", l.name, l.ms.Min, l.ms.Mean, l.ms.P50, l.ms.P95, l.ms.P99, l.ms.Max)
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"context"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var queueDlqExamples = `
+	# Execute replay 'queues' dead-letter queue command
+	kubemqctl queues dlq replay
+`
+var queueDlqLong = `Execute Kubemq 'queues' dead-letter queue commands`
+var queueDlqShort = `Execute Kubemq 'queues' dead-letter queue commands`
+
+func NewCmdQueueDlq(ctx context.Context, cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "dlq",
+		Aliases:   []string{"dead-letter"},
+		Short:     queueDlqShort,
+		Long:      queueDlqLong,
+		Example:   queueDlqExamples,
+		ValidArgs: []string{"replay"},
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.CheckErr(cmd.Help())
+		},
+	}
+	cmd.AddCommand(NewCmdQueueDlqReplay(ctx, cfg))
+	return cmd
+}
@@ -0,0 +1,177 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueueDlqReplayOptions struct {
+	cfg           *config.Config
+	transport     string
+	channel       string
+	to            string
+	metadataRegex string
+	bodyRegex     string
+	tags          []string
+	expiration    int
+	delay         int
+	maxReceive    int
+	deadLetter    string
+	rate          int
+	max           int
+	visibility    int
+	maxHeld       int
+	wait          int
+	filter        *messageFilter
+}
+
+var queueDlqReplayExamples = `
+	# Replay all messages from dead-letter queue 'dlq' back to their original queue
+	kubemqctl queue dlq replay dlq
+
+	# Replay all messages from dead-letter queue 'dlq' to queue 'orders' at 50 messages per second
+	kubemqctl queue dlq replay dlq --to orders --rate 50
+
+	# Replay only messages with metadata matching 'order-.*' and tag 'type' equal to 'payment'
+	kubemqctl queue dlq replay dlq --to orders --metadata-regex 'order-.*' --tag 'type=^payment$'
+
+	# Replay up to 100 messages with body matching 'timeout' and rewrite policy to max receive 3 and expiration of 1 hour
+	kubemqctl queue dlq replay dlq --to orders --body-regex timeout --max 100 --max-receive 3 --expiration 3600
+`
+var queueDlqReplayLong = `Replay command allows to move messages from a dead-letter queue into a queue channel, with optional filtering, policy rewrite and rate limiting. Messages that do not match the filter stay in the dead-letter queue`
+var queueDlqReplayShort = `Replay messages from a dead-letter queue command`
+
+func NewCmdQueueDlqReplay(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueDlqReplayOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "replay",
+		Aliases: []string{"rp"},
+		Short:   queueDlqReplayShort,
+		Long:    queueDlqReplayLong,
+		Example: queueDlqReplayExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.to, "to", "t", "", "set destination queue, default is the queue the message was re-routed from")
+	cmd.PersistentFlags().StringVarP(&o.metadataRegex, "metadata-regex", "", "", "set replay only messages with metadata matching regex")
+	cmd.PersistentFlags().StringVarP(&o.bodyRegex, "body-regex", "", "", "set replay only messages with body matching regex")
+	cmd.PersistentFlags().StringArrayVarP(&o.tags, "tag", "", []string{}, "set replay only messages with tag matching key=regex")
+	cmd.PersistentFlags().IntVarP(&o.expiration, "expiration", "e", -1, "set rewrite queue message expiration seconds, -1 keeps original")
+	cmd.PersistentFlags().IntVarP(&o.delay, "delay", "d", -1, "set rewrite queue message sending delay seconds, -1 keeps original")
+	cmd.PersistentFlags().IntVarP(&o.maxReceive, "max-receive", "r", -1, "set rewrite dead-letter max receive count, -1 keeps original")
+	cmd.PersistentFlags().StringVarP(&o.deadLetter, "dead-letter-queue", "q", "", "set rewrite dead-letter queue name")
+	cmd.PersistentFlags().IntVarP(&o.rate, "rate", "", 0, "set max messages per second to replay, 0 is unlimited")
+	cmd.PersistentFlags().IntVarP(&o.max, "max", "m", 0, "set max messages to replay, 0 replays all matching messages")
+	cmd.PersistentFlags().IntVarP(&o.visibility, "visibility", "v", 300, "set visibility seconds for messages held during replay")
+	cmd.PersistentFlags().IntVarP(&o.maxHeld, "max-held", "", defaultMaxHeld, "set max skipped messages held invisible during replay, each one keeps a stream open")
+	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds to wait for dead-letter queue messages")
+	return cmd
+}
+
+func (o *QueueDlqReplayOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing dead-letter queue channel argument")
+	}
+	var err error
+	o.filter, err = newMessageFilter(o.metadataRegex, o.bodyRegex, o.tags)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *QueueDlqReplayOptions) Validate() error {
+	if o.to == o.channel {
+		return fmt.Errorf("destination queue cannot be the dead-letter queue")
+	}
+	if o.rate < 0 {
+		return fmt.Errorf("rate must be zero or a positive number")
+	}
+	if o.max < 0 {
+		return fmt.Errorf("max must be zero or a positive number")
+	}
+	if err := validateScan(o.visibility, o.wait, o.maxHeld); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *QueueDlqReplayOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	scanner := &queueScanner{
+		client:     client,
+		channel:    o.channel,
+		visibility: o.visibility,
+		wait:       o.wait,
+		maxHeld:    o.maxHeld,
+		max:        o.max,
+		rate:       o.rate,
+	}
+	utils.Printlnf("replaying messages from dead-letter queue %s...", o.channel)
+	noTarget := 0
+	stats, err := scanner.scan(ctx, func(msg *kubemq2.QueueMessage) (scanAction, *kubemq2.QueueMessage, error) {
+		if !o.filter.match(msg) {
+			return scanSkip, nil, nil
+		}
+		to := o.to
+		if to == "" && msg.Attributes != nil {
+			to = msg.Attributes.ReRoutedFromQueue
+		}
+		if to == "" {
+			noTarget++
+			return scanSkip, nil, nil
+		}
+		newMsg := o.rewrite(newQueueArchiveRecord(msg).toQueueMessage(client, to))
+		utils.Printlnf("[%s] -> [%s] {id: %s, metadata: %s}", o.channel, to, msg.MessageID, msg.Metadata)
+		return scanResend, newMsg, nil
+	})
+	if stats != nil {
+		utils.Printlnf("%s, replayed %d, left %d in dead-letter queue", stats.summary(err), stats.Resent, stats.Skipped)
+	}
+	if noTarget > 0 {
+		utils.Printlnf("%d matching messages have no original queue, use --to to set a destination queue", noTarget)
+	}
+	return err
+}
+
+func (o *QueueDlqReplayOptions) rewrite(msg *kubemq2.QueueMessage) *kubemq2.QueueMessage {
+	if o.expiration >= 0 {
+		msg.SetPolicyExpirationSeconds(o.expiration)
+	}
+	if o.delay >= 0 {
+		msg.SetPolicyDelaySeconds(o.delay)
+	}
+	if o.maxReceive >= 0 {
+		msg.SetPolicyMaxReceiveCount(o.maxReceive)
+	}
+	if o.deadLetter != "" {
+		msg.SetPolicyMaxReceiveQueue(o.deadLetter)
+	}
+	return msg
+}
@@ -0,0 +1,170 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubemq-io/kubemqctl/pkg/archive"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueueExportOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	fileName  string
+	messages  int
+	batchSize int
+	wait      int
+	drain     bool
+}
+
+var queueExportExamples = `
+	# Export (peek) up to 1000 messages from queue channel q1 to q1.ndjson
+	kubemqctl queue export q1
+
+	# Export (peek) up to 5000 messages from queue channel q1 to a gzip compressed file
+	kubemqctl queue export q1 -f q1-backup.ndjson.gz -m 5000
+
+	# Export and remove (drain) all messages from queue channel q1 to a gzip compressed file
+	kubemqctl queue export q1 -f q1.ndjson.gz --drain -m 0
+`
+var queueExportLong = `Export command allows to save messages of a queue channel into a local archive file (ndjson, .gz or .tar.gz) keeping tags, policy, attributes and raw body. Peek mode exports up to the server max receive messages request (default 1024) first messages. Drain mode writes ndjson or .gz files only, a .tar.gz archive is written when the export ends so an interrupted drain would lose the removed messages`
+var queueExportShort = `Export messages from a queue channel to an archive file command`
+
+func NewCmdQueueExport(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueExportOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "export",
+		Aliases: []string{"exp"},
+		Short:   queueExportShort,
+		Long:    queueExportLong,
+		Example: queueExportExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.fileName, "file", "f", "", "set archive file name, default <channel>.ndjson")
+	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "m", 1000, "set max messages to export, 0 exports all messages (drain mode only)")
+	cmd.PersistentFlags().IntVarP(&o.batchSize, "batch-size", "", 100, "set how many messages to pull in each request (drain mode only)")
+	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds to wait for queue messages")
+	cmd.PersistentFlags().BoolVarP(&o.drain, "drain", "", false, "set remove exported messages from the queue instead of peeking them")
+	return cmd
+}
+
+func (o *QueueExportOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	if o.fileName == "" {
+		o.fileName = fmt.Sprintf("%s.ndjson", o.channel)
+	}
+	return nil
+}
+
+func (o *QueueExportOptions) Validate() error {
+	if o.messages < 0 {
+		return fmt.Errorf("messages must be zero or a positive number")
+	}
+	if !o.drain && o.messages == 0 {
+		return fmt.Errorf("peek export requires max messages greater than zero")
+	}
+	// a tar.gz archive is written on close, drained messages must be on disk before more are removed
+	if o.drain && archive.FormatFromFileName(o.fileName) == archive.FormatTarGzip {
+		return fmt.Errorf("drain mode cannot write a .tar.gz archive, use a .ndjson or .gz file")
+	}
+	if o.batchSize <= 0 {
+		return fmt.Errorf("batch size must be a positive number")
+	}
+	return nil
+}
+
+func (o *QueueExportOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	w, err := archive.NewWriter(o.fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+	if !o.drain {
+		peeked, err := peekQueue(ctx, client, o.channel, o.wait, o.messages, getQueueLimits(o.cfg).maxReceive)
+		if err != nil {
+			return err
+		}
+		for _, msg := range peeked.messages {
+			if err := w.Write(newQueueArchiveRecord(msg)); err != nil {
+				return err
+			}
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		utils.Printlnf("exported (peek) %d messages from %s to %s", w.Count(), o.channel, o.fileName)
+		if peeked.truncated {
+			utils.Printlnf("%s, use --drain to export deeper messages", peeked.note(o.channel))
+		}
+		return nil
+	}
+	maxReceive := getQueueLimits(o.cfg).maxReceive
+	for o.messages == 0 || w.Count() < o.messages {
+		batch := o.batchSize
+		if batch > maxReceive {
+			batch = maxReceive
+		}
+		if o.messages > 0 && o.messages-w.Count() < batch {
+			batch = o.messages - w.Count()
+		}
+		res, err := client.RQM().
+			SetChannel(o.channel).
+			SetWaitTimeSeconds(o.wait).
+			SetMaxNumberOfMessages(batch).
+			Send(ctx)
+		if err != nil {
+			return fmt.Errorf("receive queue messages, %s", err.Error())
+		}
+		if res.IsError {
+			return fmt.Errorf("receive queue messages, %s", res.Error)
+		}
+		if res.MessagesReceived == 0 {
+			break
+		}
+		for _, msg := range res.Messages {
+			if err := w.Write(newQueueArchiveRecord(msg)); err != nil {
+				return err
+			}
+		}
+		// received messages are gone from the queue, keep what we have on disk before pulling more
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		utils.Printlnf("drained %d messages so far...", w.Count())
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	utils.Printlnf("exported (drain) %d messages from %s to %s", w.Count(), o.channel, o.fileName)
+	return nil
+}
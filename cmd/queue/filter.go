@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// messageFilter matches queue messages by metadata, body and tags regex, age, sequence range and body jsonpath,
+// an empty filter matches all messages
+type messageFilter struct {
+	any       *regexp.Regexp
+	metadata  *regexp.Regexp
+	body      *regexp.Regexp
+	tags      map[string]*regexp.Regexp
+	olderThan time.Duration
+	seqFrom   uint64
+	seqTo     uint64
+	jsonPath  *utils.JSONPath
+	jsonValue *regexp.Regexp
+	now       func() time.Time
+}
+
+func newMessageFilter(metadata, body string, tags []string) (*messageFilter, error) {
+	f := &messageFilter{
+		tags: map[string]*regexp.Regexp{},
+		now:  time.Now,
+	}
+	var err error
+	if metadata != "" {
+		if f.metadata, err = regexp.Compile(metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata regex, %s", err.Error())
+		}
+	}
+	if body != "" {
+		if f.body, err = regexp.Compile(body); err != nil {
+			return nil, fmt.Errorf("invalid body regex, %s", err.Error())
+		}
+	}
+	for _, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid tag filter %s, tag filter must have key=regex format", tag)
+		}
+		rex, err := regexp.Compile(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %s regex, %s", kv[0], err.Error())
+		}
+		f.tags[kv[0]] = rex
+	}
+	return f, nil
+}
+
+// withAny adds a regex that must match either the message metadata or body
+func (f *messageFilter) withAny(expr string) error {
+	if expr == "" {
+		return nil
+	}
+	rex, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid filter regex, %s", err.Error())
+	}
+	f.any = rex
+	return nil
+}
+
+// withAge matches only messages that were sent more than d ago
+func (f *messageFilter) withAge(d time.Duration) {
+	f.olderThan = d
+}
+
+// withSequenceRange matches only messages with sequence in the inclusive range, zero leaves the bound open
+func (f *messageFilter) withSequenceRange(from, to uint64) error {
+	if to > 0 && from > to {
+		return fmt.Errorf("invalid sequence range %d-%d", from, to)
+	}
+	f.seqFrom, f.seqTo = from, to
+	return nil
+}
+
+// withJSONPath matches only messages with a json body where one of the values selected by expr matches the value regex.
+// An empty value regex matches any selected value
+func (f *messageFilter) withJSONPath(expr, value string) error {
+	if expr == "" {
+		if value != "" {
+			return fmt.Errorf("jsonpath value regex requires a jsonpath expression")
+		}
+		return nil
+	}
+	jp, err := utils.NewJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	f.jsonPath = jp
+	if value != "" {
+		if f.jsonValue, err = regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid jsonpath value regex, %s", err.Error())
+		}
+	}
+	return nil
+}
+
+func (f *messageFilter) isEmpty() bool {
+	return f.any == nil && f.metadata == nil && f.body == nil && len(f.tags) == 0 &&
+		f.olderThan == 0 && f.seqFrom == 0 && f.seqTo == 0 && f.jsonPath == nil
+}
+
+func (f *messageFilter) match(msg *kubemq2.QueueMessage) bool {
+	if f.olderThan > 0 || f.seqFrom > 0 || f.seqTo > 0 {
+		if msg.Attributes == nil {
+			return false
+		}
+		if f.olderThan > 0 && f.now().Sub(time.Unix(0, msg.Attributes.Timestamp)) < f.olderThan {
+			return false
+		}
+		if f.seqFrom > 0 && msg.Attributes.Sequence < f.seqFrom {
+			return false
+		}
+		if f.seqTo > 0 && msg.Attributes.Sequence > f.seqTo {
+			return false
+		}
+	}
+	if f.jsonPath != nil && !f.matchJSONPath(msg.Body) {
+		return false
+	}
+	if f.any != nil && !f.any.MatchString(msg.Metadata) && !f.any.Match(msg.Body) {
+		return false
+	}
+	if f.metadata != nil && !f.metadata.MatchString(msg.Metadata) {
+		return false
+	}
+	if f.body != nil && !f.body.Match(msg.Body) {
+		return false
+	}
+	for key, rex := range f.tags {
+		value, ok := msg.Tags[key]
+		if !ok || !rex.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *messageFilter) matchJSONPath(body []byte) bool {
+	values := f.jsonPath.Values(body)
+	if f.jsonValue == nil {
+		return len(values) > 0
+	}
+	for _, v := range values {
+		if f.jsonValue.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
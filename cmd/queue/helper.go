@@ -1,12 +1,13 @@
 package queue
 
 import (
-	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	kubemq "github.com/kubemq-io/kubemq-go"
-	"time"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 )
 
 type queueMessageObject struct {
@@ -23,9 +24,11 @@ type queueMessageObject struct {
 	Tags                      map[string]string `json:"tags,omitempty"`
 	BodyJson                  json.RawMessage   `json:"body_json,omitempty"`
 	BodyString                string            `json:"body_string,omitempty"`
+	DecodeError               string            `json:"decode_error,omitempty"`
+	SchemaError               string            `json:"schema_error,omitempty"`
 }
 
-func newQueueMessageObject(msg *kubemq.QueueMessage) *queueMessageObject {
+func newQueueMessageObject(msg *kubemq.QueueMessage, dec decoder.Decoder) *queueMessageObject {
 	obj := &queueMessageObject{
 		Id:         msg.MessageID,
 		Channel:    msg.Channel,
@@ -59,33 +62,36 @@ func newQueueMessageObject(msg *kubemq.QueueMessage) *queueMessageObject {
 			obj.ExpireAt = time.Unix(0, msg.Attributes.ExpirationAt).Format("2006-01-02 15:04:05.999")
 		}
 	}
-	var js json.RawMessage
-	if err := json.Unmarshal(msg.Body, &js); err == nil {
-		obj.BodyJson = js
-	} else {
-		sDec, err := b64.StdEncoding.DecodeString(string(msg.Body))
-		if err != nil {
-			obj.BodyString = string(msg.Body)
-		} else {
-			obj.BodyString = string(sDec)
-		}
+	var err error
+	if obj.BodyJson, obj.BodyString, err = decoder.Body(dec, msg.Body); err != nil {
+		obj.DecodeError = err.Error()
 	}
 
 	return obj
 }
 
+// withSchema validates the decoded body against the schema, a validation error is set as the object schema error
+func (o *queueMessageObject) withSchema(v *schema.Validator) *queueMessageObject {
+	body := []byte(o.BodyJson)
+	if len(body) == 0 {
+		body = []byte(o.BodyString)
+	}
+	o.SchemaError = schema.Error(v, body)
+	return o
+}
+
 func (o *queueMessageObject) String() string {
 	data, _ := json.MarshalIndent(o, "", "    ")
 	return string(data)
 }
 
-func printItems(items []*kubemq.QueueMessage) {
+func printItems(items []*kubemq.QueueMessage, dec decoder.Decoder, v *schema.Validator) {
 	for _, item := range items {
-		fmt.Println(newQueueMessageObject(item))
+		fmt.Println(newQueueMessageObject(item, dec).withSchema(v))
 	}
 }
 func printQueueMessage(msg *kubemq.QueueMessage) {
-	fmt.Println(newQueueMessageObject(msg))
+	fmt.Println(newQueueMessageObject(msg, nil))
 }
 
 type resultObj struct {
@@ -0,0 +1,141 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kubemq-io/kubemqctl/pkg/archive"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueueImportOptions struct {
+	cfg       *config.Config
+	transport string
+	channel   string
+	fileName  string
+	batchSize int
+}
+
+var queueImportExamples = `
+	# Import messages from q1.ndjson archive into queue channel q1
+	kubemqctl queue import q1
+
+	# Import messages from a gzip compressed archive into queue channel q2
+	kubemqctl queue import q2 -f q1-backup.ndjson.gz
+
+	# Import messages from a tar.gz archive into queue channel q1 in batches of 500 messages
+	kubemqctl queue import q1 -f q1.tar.gz --batch-size 500
+`
+var queueImportLong = `Import command allows to send messages from a local archive file (created by export command) into a queue channel keeping tags, metadata and policy`
+var queueImportShort = `Import messages from an archive file to a queue channel command`
+
+func NewCmdQueueImport(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueImportOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "import",
+		Aliases: []string{"imp"},
+		Short:   queueImportShort,
+		Long:    queueImportLong,
+		Example: queueImportExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&o.fileName, "file", "f", "", "set archive file name, default <channel>.ndjson")
+	cmd.PersistentFlags().IntVarP(&o.batchSize, "batch-size", "", 100, "set how many messages to send in each batch request")
+	return cmd
+}
+
+func (o *QueueImportOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	if o.fileName == "" {
+		o.fileName = fmt.Sprintf("%s.ndjson", o.channel)
+	}
+	return nil
+}
+
+func (o *QueueImportOptions) Validate() error {
+	if o.batchSize <= 0 {
+		return fmt.Errorf("batch size must be a positive number")
+	}
+	return nil
+}
+
+func (o *QueueImportOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	r, err := archive.NewReader(o.fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	sent, failed := 0, 0
+	batch := client.QMB()
+	send := func() error {
+		if len(batch.Messages) == 0 {
+			return nil
+		}
+		results, err := batch.Send(ctx)
+		if err != nil {
+			return fmt.Errorf("send queue messages batch, %s", err.Error())
+		}
+		for _, res := range results {
+			if res.IsError {
+				failed++
+				utils.Printlnf("message %s failed, %s", res.MessageID, res.Error)
+				continue
+			}
+			sent++
+		}
+		utils.Printlnf("imported %d messages so far...", sent)
+		batch = client.QMB()
+		return nil
+	}
+	for {
+		rec := &queueArchiveRecord{}
+		err := r.Next(rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		batch.Add(rec.toQueueMessage(client, o.channel))
+		if len(batch.Messages) >= o.batchSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := send(); err != nil {
+		return err
+	}
+	utils.Printlnf("imported %d messages from %s to %s, %d failed", sent, o.fileName, o.channel, failed)
+	return nil
+}
@@ -1,9 +1,11 @@
 package queue
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/fatih/color"
 	"github.com/go-resty/resty/v2"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
@@ -19,6 +21,11 @@ type QueueListOptions struct {
 	cfg       *config.Config
 	transport string
 	filter    string
+	watch     bool
+	interval  time.Duration
+	alertList []string
+	once      bool
+	alerts    queueAlerts
 }
 
 var queueListExamples = `
@@ -27,6 +34,15 @@ var queueListExamples = `
 	
 	# Get a list of queues / clients filtered by 'some-queue' channel only
 	kubemqctl queue list -f some-queue
+
+	# Watch queues depth and in / out rates, refresh every 5 seconds
+	kubemqctl queue list --watch --interval 5s
+
+	# Watch queues and highlight 'orders' queue when it has more than 1000 messages
+	kubemqctl queue list --watch --alert 'orders>1000'
+
+	# Check queues depth once and exit with non-zero code when any 'orders-*' queue has more than 1000 messages
+	kubemqctl queue list --once --alert 'orders-*>1000'
 `
 var queueListLong = `List command allows to get a list of 'queues' channels / clients with details`
 var queueListShort = `Get a list of 'queues' channels / clients command`
@@ -52,39 +68,72 @@ func NewCmdQueueList(ctx context.Context, cfg *config.Config) *cobra.Command {
 		},
 	}
 	cmd.PersistentFlags().StringVarP(&o.filter, "filter", "f", "", "set filter for channel / client name")
+	cmd.PersistentFlags().BoolVarP(&o.watch, "watch", "", false, "set watch mode, redraw the list every interval with in / out rates")
+	cmd.PersistentFlags().DurationVarP(&o.interval, "interval", "", 5*time.Second, "set watch mode refresh interval")
+	cmd.PersistentFlags().StringArrayVarP(&o.alertList, "alert", "", []string{}, "set alert threshold on channel messages, i.e. 'orders>1000', channel can be a glob pattern")
+	cmd.PersistentFlags().BoolVarP(&o.once, "once", "", false, "set print list once and exit with non-zero code when any alert is triggered")
 	return cmd
 }
 
 func (o *QueueListOptions) Complete(args []string, transport string) error {
 	o.transport = transport
+	for _, expr := range o.alertList {
+		alert, err := parseQueueAlert(expr)
+		if err != nil {
+			return err
+		}
+		o.alerts = append(o.alerts, alert)
+	}
 	return nil
 }
 
 func (o *QueueListOptions) Validate() error {
+	if o.watch && o.once {
+		return fmt.Errorf("watch and once flags cannot be set together")
+	}
+	if o.watch && o.interval < time.Second {
+		return fmt.Errorf("interval must be at least 1 second")
+	}
 	return nil
 }
 
 func (o *QueueListOptions) Run(ctx context.Context) error {
+	if o.watch {
+		return o.runWatch(ctx)
+	}
+	q, err := o.getQueues(ctx)
+	if err != nil {
+		return err
+	}
+	triggered := o.alerts.check(q, o.filter)
+	q.printChannelsTab(o.filter, nil, triggered)
+	q.printClientsTab(o.filter)
+	printTriggeredAlerts(triggered)
+	if o.once && len(triggered) > 0 {
+		return fmt.Errorf("%d queue alerts triggered", len(triggered))
+	}
+	return nil
+}
+
+func (o *QueueListOptions) getQueues(ctx context.Context) (*Queues, error) {
 	resp := &Response{}
 	q := &Queues{}
 
-	r, err := resty.New().R().SetResult(resp).SetError(resp).Get(fmt.Sprintf("%s/v1/stats/queues", o.cfg.GetApiHttpURI()))
+	r, err := resty.New().R().SetContext(ctx).SetResult(resp).SetError(resp).Get(fmt.Sprintf("%s/v1/stats/queues", o.cfg.GetApiHttpURI()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !r.IsSuccess() {
-		return fmt.Errorf("not available in current Kubemq version, consider upgrade Kubemq version")
+		return nil, fmt.Errorf("not available in current Kubemq version, consider upgrade Kubemq version")
 	}
 	if resp.Error {
-		return fmt.Errorf(resp.ErrorString)
+		return nil, fmt.Errorf(resp.ErrorString)
 	}
 	err = json.Unmarshal(resp.Data, q)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	q.printChannelsTab(o.filter)
-	q.printClientsTab(o.filter)
-	return nil
+	return q, nil
 }
 
 type Response struct {
@@ -117,20 +166,47 @@ type Client struct {
 	Pending          int64  `json:"pending"`
 }
 
-func (q *Queues) printChannelsTab(filter string) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
+// printChannelsTab prints the channels table, rates columns are added when rates are set and rows of channels with
+// triggered alerts are highlighted
+func (q *Queues) printChannelsTab(filter string, rates map[string]*queueRate, triggered map[string][]string) {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', tabwriter.TabIndent)
 	fmt.Fprintf(w, "CHANNELS:\n")
-	fmt.Fprintln(w, "NAME\tCLIENTS\tMESSAGES\tBYTES\tFIRST_SEQUENCE\tLAST_SEQUENCE")
+	if rates != nil {
+		fmt.Fprintln(w, "NAME\tCLIENTS\tMESSAGES\tBYTES\tFIRST_SEQUENCE\tLAST_SEQUENCE\tIN/SEC\tOUT/SEC")
+	} else {
+		fmt.Fprintln(w, "NAME\tCLIENTS\tMESSAGES\tBYTES\tFIRST_SEQUENCE\tLAST_SEQUENCE")
+	}
 	cnt := 0
+	highlight := map[int]bool{}
 	for _, q := range q.Queues {
 		if filter == "" || strings.Contains(q.Name, filter) {
-			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", q.Name, len(q.Clients), q.Messages, q.Bytes, q.FirstSequence, q.LastSequence)
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d", q.Name, len(q.Clients), q.Messages, q.Bytes, q.FirstSequence, q.LastSequence)
+			if rates != nil {
+				if rate, ok := rates[q.Name]; ok {
+					fmt.Fprintf(w, "\t%.1f\t%.1f", rate.In, rate.Out)
+				} else {
+					fmt.Fprintf(w, "\t-\t-")
+				}
+			}
+			fmt.Fprintln(w)
 			cnt++
+			if len(triggered[q.Name]) > 0 {
+				highlight[cnt+1] = true
+			}
 		}
 
 	}
 	fmt.Fprintf(w, "\nTOTAL CHANNELS:\t%d\n", cnt)
 	w.Flush()
+	// rows are colored after alignment, escape codes would break the tabwriter columns width
+	red := color.New(color.FgHiRed, color.Bold).SprintFunc()
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if highlight[i] {
+			line = red(line)
+		}
+		fmt.Println(line)
+	}
 }
 func (q *Queues) printClientsTab(filter string) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
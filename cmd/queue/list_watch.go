@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// queueAlert is a threshold on a channel messages count, channel can be a glob pattern
+type queueAlert struct {
+	expr      string
+	channel   string
+	op        string
+	threshold int64
+}
+
+type queueAlerts []*queueAlert
+
+var queueAlertRegex = regexp.MustCompile(`^\s*([^<>=!]+?)\s*(>=|<=|==|!=|>|<|=)\s*(\d+)\s*$`)
+
+func parseQueueAlert(expr string) (*queueAlert, error) {
+	parts := queueAlertRegex.FindStringSubmatch(expr)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid alert %s, alert must have channel<op>messages format, i.e. orders>1000", expr)
+	}
+	if _, err := path.Match(parts[1], ""); err != nil {
+		return nil, fmt.Errorf("invalid alert %s channel pattern, %s", expr, err.Error())
+	}
+	threshold, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert %s threshold, %s", expr, err.Error())
+	}
+	return &queueAlert{
+		expr:      expr,
+		channel:   parts[1],
+		op:        parts[2],
+		threshold: threshold,
+	}, nil
+}
+
+func (a *queueAlert) match(q *Queue) bool {
+	if ok, _ := path.Match(a.channel, q.Name); !ok {
+		return false
+	}
+	switch a.op {
+	case ">":
+		return q.Messages > a.threshold
+	case ">=":
+		return q.Messages >= a.threshold
+	case "<":
+		return q.Messages < a.threshold
+	case "<=":
+		return q.Messages <= a.threshold
+	case "!=":
+		return q.Messages != a.threshold
+	default:
+		return q.Messages == a.threshold
+	}
+}
+
+// check returns the triggered alerts expressions by channel name
+func (alerts queueAlerts) check(q *Queues, filter string) map[string][]string {
+	triggered := map[string][]string{}
+	for _, queue := range q.Queues {
+		if filter != "" && !strings.Contains(queue.Name, filter) {
+			continue
+		}
+		for _, alert := range alerts {
+			if alert.match(queue) {
+				triggered[queue.Name] = append(triggered[queue.Name], fmt.Sprintf("%s (messages: %d)", alert.expr, queue.Messages))
+			}
+		}
+	}
+	return triggered
+}
+
+func printTriggeredAlerts(triggered map[string][]string) {
+	if len(triggered) == 0 {
+		return
+	}
+	var channels []string
+	for channel := range triggered {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	utils.Printlnf("\nALERTS:")
+	for _, channel := range channels {
+		for _, alert := range triggered[channel] {
+			utils.Printlnf("%s: %s", channel, alert)
+		}
+	}
+}
+
+// queueRate is the per second rate of messages sent to and removed from a channel between two snapshots
+type queueRate struct {
+	In  float64
+	Out float64
+}
+
+func calcQueueRates(prev, curr *Queues, elapsed time.Duration) map[string]*queueRate {
+	rates := map[string]*queueRate{}
+	if prev == nil || elapsed <= 0 {
+		return rates
+	}
+	last := map[string]*Queue{}
+	for _, q := range prev.Queues {
+		last[q.Name] = q
+	}
+	seconds := elapsed.Seconds()
+	for _, q := range curr.Queues {
+		p, ok := last[q.Name]
+		if !ok {
+			continue
+		}
+		in := q.LastSequence - p.LastSequence
+		if in < 0 {
+			in = 0
+		}
+		// messages that arrived but did not add to the depth were consumed, acked or expired
+		out := in - (q.Messages - p.Messages)
+		if out < 0 {
+			out = 0
+		}
+		rates[q.Name] = &queueRate{
+			In:  float64(in) / seconds,
+			Out: float64(out) / seconds,
+		}
+	}
+	return rates
+}
+
+func (o *QueueListOptions) runWatch(ctx context.Context) error {
+	var prev *Queues
+	var prevTime time.Time
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		q, err := o.getQueues(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		now := time.Now()
+		rates := calcQueueRates(prev, q, now.Sub(prevTime))
+		triggered := o.alerts.check(q, o.filter)
+		// clear screen and move the cursor home before redrawing
+		fmt.Print("\033[H\033[2J")
+		utils.Printlnf("Every %s: queues list (%s)\n", o.interval, now.Format("2006-01-02 15:04:05"))
+		q.printChannelsTab(o.filter, rates, triggered)
+		q.printClientsTab(o.filter)
+		printTriggeredAlerts(triggered)
+		prev, prevTime = q, now
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -4,18 +4,24 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type QueuePeekOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	messages  int
-	wait      int
+	cfg           *config.Config
+	transport     string
+	channel       string
+	messages      int
+	wait          int
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var queuePeekExamples = `
@@ -24,6 +30,9 @@ var queuePeekExamples = `
 
 	# Peek 3 messages from a queue and wait for 5 seconds
 	kubemqctl queue peek some-channel -m 3 -w 5
+
+	# Peek 10 messages from a queue with gzip compressed protobuf bodies
+	kubemqctl queue peek some-channel -m 10 --decode gzip,protobuf --proto-descriptor orders.pb --proto-message orders.v1.Order
 `
 var queuePeekLong = `Peek command allows to peek one or many messages from a queue channel without removing them from the queue`
 var queuePeekShort = `Peek a messages from a queue channel command`
@@ -51,7 +60,9 @@ func NewCmdQueuePeek(ctx context.Context, cfg *config.Config) *cobra.Command {
 
 	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "m", 1, "set how many messages we want to peek from queue")
 	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds to wait for peeking queue messages")
+	o.decodeOptions.AddFlags(cmd)
 
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -59,9 +70,17 @@ func (o *QueuePeekOptions) Complete(args []string, transport string) error {
 	o.transport = transport
 	if len(args) >= 1 {
 		o.channel = args[0]
-		return nil
+	} else {
+		return fmt.Errorf("missing channel argument")
+	}
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
 	}
-	return fmt.Errorf("missing channel argument")
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (o *QueuePeekOptions) Validate() error {
@@ -92,7 +111,7 @@ func (o *QueuePeekOptions) Run(ctx context.Context) error {
 
 	if res.MessagesReceived > 0 {
 		utils.Printlnf("peeking %d messages", res.MessagesReceived)
-		printItems(res.Messages)
+		printItems(res.Messages, o.decoder, o.validator)
 	} else {
 		utils.Printlnf("no messages in queue to peek")
 	}
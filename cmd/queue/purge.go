@@ -0,0 +1,234 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueuePurgeOptions struct {
+	cfg           *config.Config
+	transport     string
+	channel       string
+	olderThan     time.Duration
+	metadataRegex string
+	bodyRegex     string
+	tags          []string
+	jsonPath      string
+	jsonPathRegex string
+	fromSequence  uint64
+	toSequence    uint64
+	max           int
+	dryRun        bool
+	force         bool
+	visibility    int
+	maxHeld       int
+	wait          int
+	filter        *messageFilter
+}
+
+var queuePurgeExamples = `
+	# Remove messages older than 24 hours from queue 'orders'
+	kubemqctl queue purge orders --older-than 24h
+
+	# Remove messages with metadata matching 'test-.*' and tag 'env' equal to 'staging'
+	kubemqctl queue purge orders --metadata-regex 'test-.*' --tag 'env=^staging$'
+
+	# Remove messages with json body field 'status' equal to 'cancelled'
+	kubemqctl queue purge orders --jsonpath '.status' --jsonpath-regex '^cancelled$'
+
+	# Remove messages with sequence 100 to 200
+	kubemqctl queue purge orders --from-sequence 100 --to-sequence 200
+
+	# Show which messages would be removed without removing them
+	kubemqctl queue purge orders --older-than 1h --dry-run
+
+	# Remove matching messages even when kept messages have a max receive count policy
+	kubemqctl queue purge orders --older-than 24h --force
+`
+var queuePurgeLong = `Purge command allows to remove only messages matching a set of predicates from a queue channel, messages that do not match stay in the queue. Use 'queue ack' to clear all messages of a queue channel`
+var queuePurgeShort = `Remove matching messages from a queue channel command`
+
+func NewCmdQueuePurge(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueuePurgeOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "purge",
+		Aliases: []string{"pu", "prune"},
+		Short:   queuePurgeShort,
+		Long:    queuePurgeLong,
+		Example: queuePurgeExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	cmd.PersistentFlags().DurationVarP(&o.olderThan, "older-than", "", 0, "set remove only messages older than duration, i.e. 30m, 24h")
+	cmd.PersistentFlags().StringVarP(&o.metadataRegex, "metadata-regex", "", "", "set remove only messages with metadata matching regex")
+	cmd.PersistentFlags().StringVarP(&o.bodyRegex, "body-regex", "", "", "set remove only messages with body matching regex")
+	cmd.PersistentFlags().StringArrayVarP(&o.tags, "tag", "", []string{}, "set remove only messages with tag matching key=regex")
+	cmd.PersistentFlags().StringVarP(&o.jsonPath, "jsonpath", "", "", "set remove only messages with json body where jsonpath expression selects a value")
+	cmd.PersistentFlags().StringVarP(&o.jsonPathRegex, "jsonpath-regex", "", "", "set remove only messages where a value selected by --jsonpath matches regex")
+	cmd.PersistentFlags().Uint64VarP(&o.fromSequence, "from-sequence", "", 0, "set remove only messages with sequence greater or equal to")
+	cmd.PersistentFlags().Uint64VarP(&o.toSequence, "to-sequence", "", 0, "set remove only messages with sequence less or equal to")
+	cmd.PersistentFlags().IntVarP(&o.max, "max", "m", 0, "set max messages to remove, 0 removes all matching messages")
+	cmd.PersistentFlags().BoolVarP(&o.dryRun, "dry-run", "", false, "set list matching messages without removing them")
+	cmd.PersistentFlags().BoolVarP(&o.force, "force", "", false, "set purge even when kept messages have a max receive count policy, their receive count is incremented and they may be dead-lettered")
+	cmd.PersistentFlags().IntVarP(&o.visibility, "visibility", "v", 300, "set visibility seconds for messages held during purge")
+	cmd.PersistentFlags().IntVarP(&o.maxHeld, "max-held", "", defaultMaxHeld, "set max skipped messages held invisible during purge, each one keeps a stream open")
+	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds to wait for queue messages")
+	return cmd
+}
+
+func (o *QueuePurgeOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 1 {
+		o.channel = args[0]
+	} else {
+		return fmt.Errorf("missing queue channel argument")
+	}
+	var err error
+	o.filter, err = newMessageFilter(o.metadataRegex, o.bodyRegex, o.tags)
+	if err != nil {
+		return err
+	}
+	o.filter.withAge(o.olderThan)
+	if err := o.filter.withSequenceRange(o.fromSequence, o.toSequence); err != nil {
+		return err
+	}
+	return o.filter.withJSONPath(o.jsonPath, o.jsonPathRegex)
+}
+
+func (o *QueuePurgeOptions) Validate() error {
+	if o.filter.isEmpty() {
+		return fmt.Errorf("at least one predicate must be set, use 'kubemqctl queue ack %s' to remove all messages", o.channel)
+	}
+	if o.olderThan < 0 {
+		return fmt.Errorf("older-than must be a positive duration")
+	}
+	if o.max < 0 {
+		return fmt.Errorf("max must be zero or a positive number")
+	}
+	if err := validateScan(o.visibility, o.wait, o.maxHeld); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *QueuePurgeOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	if o.dryRun {
+		return o.runDryRun(ctx, client)
+	}
+	if !o.force {
+		if err := o.checkKeptPolicy(ctx, client); err != nil {
+			return err
+		}
+	}
+	scanner := &queueScanner{
+		client:     client,
+		channel:    o.channel,
+		visibility: o.visibility,
+		wait:       o.wait,
+		maxHeld:    o.maxHeld,
+		max:        o.max,
+	}
+	utils.Printlnf("purging matching messages from queue %s...", o.channel)
+	policyKept := 0
+	stats, err := scanner.scan(ctx, func(msg *kubemq2.QueueMessage) (scanAction, *kubemq2.QueueMessage, error) {
+		if !o.filter.match(msg) {
+			if hasMaxReceivePolicy(msg) {
+				// messages deeper than the peek check show up during the scan
+				if !o.force {
+					return scanSkip, nil, fmt.Errorf("message %s to keep has a max receive count policy, purge stopped, use --force to purge anyway", msg.MessageID)
+				}
+				policyKept++
+			}
+			return scanSkip, nil, nil
+		}
+		utils.Printlnf("[%s] removed {id: %s, metadata: %s}", o.channel, msg.MessageID, msg.Metadata)
+		return scanAck, nil, nil
+	})
+	if stats != nil {
+		utils.Printlnf("%s, removed %d, kept %d in queue %s", stats.summary(err), stats.Acked, stats.Skipped, o.channel)
+	}
+	if policyKept > 0 {
+		utils.Printlnf("warning: %d kept messages have a max receive count policy, their receive count was incremented", policyKept)
+	}
+	return err
+}
+
+// checkKeptPolicy returns an error when messages which are kept have a max receive count policy, the scan receives
+// and rejects kept messages so each purge counts as a receive and may move them to the dead-letter queue. Only the
+// peeked messages are checked, deeper messages are checked by the scan as they are received
+func (o *QueuePurgeOptions) checkKeptPolicy(ctx context.Context, client *kubemq2.Client) error {
+	peeked, err := peekQueue(ctx, client, o.channel, o.wait, 0, getQueueLimits(o.cfg).maxReceive)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, msg := range peeked.messages {
+		if !o.filter.match(msg) && hasMaxReceivePolicy(msg) {
+			count++
+		}
+	}
+	if count > 0 {
+		return fmt.Errorf("%d messages to keep have a max receive count policy, purge would count a receive for each one and may dead-letter them, use --force to purge anyway", count)
+	}
+	return nil
+}
+
+func hasMaxReceivePolicy(msg *kubemq2.QueueMessage) bool {
+	return msg.Policy != nil && msg.Policy.MaxReceiveCount > 0
+}
+
+// runDryRun peeks the queue and lists the messages that would be removed
+func (o *QueuePurgeOptions) runDryRun(ctx context.Context, client *kubemq2.Client) error {
+	peeked, err := peekQueue(ctx, client, o.channel, o.wait, 0, getQueueLimits(o.cfg).maxReceive)
+	if err != nil {
+		return err
+	}
+	if len(peeked.messages) == 0 {
+		utils.Printlnf("no messages in queue %s", o.channel)
+		return nil
+	}
+	scanned, matched := 0, 0
+	for _, msg := range peeked.messages {
+		if o.max > 0 && matched >= o.max {
+			break
+		}
+		scanned++
+		if !o.filter.match(msg) {
+			continue
+		}
+		matched++
+		utils.Printlnf("[%s] would remove {id: %s, metadata: %s}", o.channel, msg.MessageID, msg.Metadata)
+	}
+	utils.Printlnf("dry-run: scanned %d messages, %d would be removed, %d kept", scanned, matched, scanned-matched)
+	if scanned < len(peeked.messages) {
+		utils.Printlnf("dry-run stopped at --max %d, the rest of the queue was not scanned", o.max)
+	} else if peeked.truncated {
+		utils.Printlnf("%s, the rest of the queue was not scanned", peeked.note(o.channel))
+	}
+	return nil
+}
@@ -28,6 +28,27 @@ var queueExamples = `
 
 	# Execute stream 'queues' command
 	kubemqctl queues stream
+
+	# Execute export 'queues' command
+	kubemqctl queues export
+
+	# Execute import 'queues' command
+	kubemqctl queues import
+
+	# Execute dead-letter queue 'queues' commands
+	kubemqctl queues dlq
+
+	# Execute move 'queues' command
+	kubemqctl queues move
+
+	# Execute copy 'queues' command
+	kubemqctl queues copy
+
+	# Execute purge 'queues' command
+	kubemqctl queues purge
+
+	# Execute bench 'queues' command
+	kubemqctl queues bench
 `
 var queueLong = `Execute Kubemq 'queues' commands`
 var queueShort = `Execute Kubemq 'queues' commands`
@@ -41,7 +62,7 @@ func NewCmdQueue(ctx context.Context, cfg *config.Config) *cobra.Command {
 		Short:     queueShort,
 		Long:      queueLong,
 		Example:   queueExamples,
-		ValidArgs: []string{"send", "receive", "attach", "peek", "ack", "list", "stream"},
+		ValidArgs: []string{"send", "receive", "attach", "peek", "ack", "list", "stream", "export", "import", "dlq", "move", "copy", "purge", "bench"},
 		Run: func(cmd *cobra.Command, args []string) {
 			utils.CheckErr(cmd.Help())
 		},
@@ -53,6 +74,13 @@ func NewCmdQueue(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.AddCommand(NewCmdQueueList(ctx, cfg))
 	cmd.AddCommand(NewCmdQueueStream(ctx, cfg))
 	cmd.AddCommand(NewCmdQueueAttach(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueExport(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueImport(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueDlq(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueMove(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueCopy(ctx, cfg))
+	cmd.AddCommand(NewCmdQueuePurge(ctx, cfg))
+	cmd.AddCommand(NewCmdQueueBench(ctx, cfg))
 
 	return cmd
 }
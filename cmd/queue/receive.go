@@ -4,19 +4,25 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 type QueueReceiveOptions struct {
-	cfg       *config.Config
-	transport string
-	channel   string
-	messages  int
-	wait      int
-	watch     bool
+	cfg           *config.Config
+	transport     string
+	channel       string
+	messages      int
+	wait          int
+	watch         bool
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var queueReceiveExamples = `
@@ -28,6 +34,12 @@ var queueReceiveExamples = `
 
 	# Watching 'queues' channel messages
 	kubemqctl queue receive q1 -w
+
+	# Receive 1 message from a queue channel q1 with msgpack body
+	kubemqctl queue receive q1 --decode msgpack
+
+	# Receive 1 message from a queue channel q1 and flag a body not valid against a JSON Schema
+	kubemqctl queue receive q1 --schema order.schema.json
 `
 var queueReceiveLong = `Receive command allows to receive one or many messages from a queue channel`
 var queueReceiveShort = `Receive a messages from a queue channel command`
@@ -56,7 +68,9 @@ func NewCmdQueueReceive(ctx context.Context, cfg *config.Config) *cobra.Command
 	cmd.PersistentFlags().IntVarP(&o.messages, "messages", "m", 1, "set how many messages we want to get from a queue")
 	cmd.PersistentFlags().IntVarP(&o.wait, "wait-timeout", "t", 2, "set how many seconds to wait for 'queues' messages")
 	cmd.PersistentFlags().BoolVarP(&o.watch, "watch", "w", false, "set watch on 'queues' channel")
+	o.decodeOptions.AddFlags(cmd)
 
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -64,9 +78,17 @@ func (o *QueueReceiveOptions) Complete(args []string, transport string) error {
 	o.transport = transport
 	if len(args) >= 1 {
 		o.channel = args[0]
-		return nil
+	} else {
+		return fmt.Errorf("missing channel argument")
 	}
-	return fmt.Errorf("missing channel argument")
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
+	}
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (o *QueueReceiveOptions) Validate() error {
@@ -101,7 +123,7 @@ func (o *QueueReceiveOptions) Run(ctx context.Context) error {
 		}
 
 		if res != nil && res.MessagesReceived > 0 {
-			printItems(res.Messages)
+			printItems(res.Messages, o.decoder, o.validator)
 		} else if !o.watch {
 			utils.Println("No new messages in queue")
 
@@ -0,0 +1,243 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+)
+
+type scanAction int
+
+const (
+	// scanSkip leaves the message in the queue, it is held invisible until the scan ends and then rejected back
+	scanSkip scanAction = iota
+	// scanAck removes the message from the queue
+	scanAck
+	// scanResend acks the message and sends the replacement message in the same transaction
+	scanResend
+	// scanStop ends the scan, the current message is treated as skipped
+	scanStop
+)
+
+type scanStats struct {
+	Scanned int
+	Acked   int
+	Resent  int
+	Skipped int
+}
+
+type scanHandler func(msg *kubemq2.QueueMessage) (scanAction, *kubemq2.QueueMessage, error)
+
+// queueScanner walks a queue channel in transaction mode using the stream API, one message per stream
+type queueScanner struct {
+	client     *kubemq2.Client
+	channel    string
+	visibility int
+	wait       int
+	max        int
+	maxHeld    int
+	rate       int
+}
+
+// defaultMaxHeld is the default of how many skipped messages a scan holds, each one keeps a stream open
+const defaultMaxHeld = 1000
+
+// scanIncompleteError is returned when a scan stops before the whole queue was scanned
+type scanIncompleteError struct {
+	reason string
+}
+
+func (e *scanIncompleteError) Error() string {
+	return fmt.Sprintf("scan stopped early, %s, the rest of the queue was not scanned", e.reason)
+}
+
+// validateScan validates the scan flags, held messages must stay invisible for longer than a receive wait
+func validateScan(visibility, wait, maxHeld int) error {
+	if visibility <= 0 || wait <= 0 {
+		return fmt.Errorf("visibility and wait must be positive numbers")
+	}
+	if visibility <= wait+2 {
+		return fmt.Errorf("visibility must be more than wait + 2 seconds")
+	}
+	if maxHeld <= 0 {
+		return fmt.Errorf("max-held must be a positive number")
+	}
+	return nil
+}
+
+// summary returns the prefix of a scan result line, partial when the scan stopped early on an error
+func (s *scanStats) summary(err error) string {
+	if err != nil {
+		return fmt.Sprintf("scan stopped early: scanned %d messages", s.Scanned)
+	}
+	return fmt.Sprintf("scanned %d messages", s.Scanned)
+}
+
+type heldMessage struct {
+	stream *kubemq2.StreamQueueMessage
+	msg    *kubemq2.QueueMessage
+}
+
+// scan calls handler for each message until the queue is empty, handler asks to stop or max acked/resent messages were
+// processed. Skipped messages are held invisible so they are not received again and are rejected back to the queue
+// before scan returns. The scan stops with a scanIncompleteError before max held messages are exceeded or the
+// visibility of the first held message expires, a held message showing up again also means its visibility expired
+func (s *queueScanner) scan(ctx context.Context, handler scanHandler) (*scanStats, error) {
+	stats := &scanStats{}
+	var held []*heldMessage
+	seen := map[uint64]bool{}
+	defer func() {
+		for _, h := range held {
+			_ = h.msg.Reject()
+			h.stream.Close()
+		}
+	}()
+	maxHeld := s.maxHeld
+	if maxHeld <= 0 {
+		maxHeld = defaultMaxHeld
+	}
+	// stop while the held messages are still invisible, a receive can take the wait time
+	budget := time.Duration(s.visibility-s.wait-2) * time.Second
+	var heldSince time.Time
+	var ticker *time.Ticker
+	if s.rate > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(s.rate))
+		defer ticker.Stop()
+	}
+	for s.max == 0 || stats.Acked+stats.Resent < s.max {
+		if len(held) > 0 && time.Since(heldSince) >= budget {
+			return stats, &scanIncompleteError{reason: fmt.Sprintf("visibility of %d skipped messages expires, raise --visibility", len(held))}
+		}
+		stream := s.client.NewStreamQueueMessage().SetChannel(s.channel)
+		msg, err := stream.Next(ctx, int32(s.visibility), int32(s.wait))
+		if err != nil {
+			stream.Close()
+			if isQueueEmptyErr(err) {
+				return stats, nil
+			}
+			return stats, fmt.Errorf("receive queue message, %s", err.Error())
+		}
+		if msg == nil {
+			stream.Close()
+			return stats, ctx.Err()
+		}
+		if msg.Attributes != nil {
+			if seen[msg.Attributes.Sequence] {
+				_ = msg.Reject()
+				stream.Close()
+				return stats, &scanIncompleteError{reason: fmt.Sprintf("skipped message %s became visible again, raise --visibility", msg.MessageID)}
+			}
+			seen[msg.Attributes.Sequence] = true
+		}
+		stats.Scanned++
+		action, newMsg, err := handler(msg)
+		if err != nil {
+			_ = msg.Reject()
+			stream.Close()
+			return stats, err
+		}
+		if ticker != nil && (action == scanAck || action == scanResend) {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				_ = msg.Reject()
+				stream.Close()
+				return stats, ctx.Err()
+			}
+		}
+		switch action {
+		case scanAck:
+			if err := msg.Ack(); err != nil {
+				stream.Close()
+				return stats, fmt.Errorf("ack queue message %s, %s", msg.MessageID, err.Error())
+			}
+			stats.Acked++
+		case scanResend:
+			if err := stream.ResendWithNewMessage(newMsg); err != nil {
+				stream.Close()
+				return stats, fmt.Errorf("resend queue message %s, %s", msg.MessageID, err.Error())
+			}
+			stats.Resent++
+		case scanStop:
+			_ = msg.Reject()
+			stream.Close()
+			return stats, nil
+		default:
+			if len(held) >= maxHeld {
+				_ = msg.Reject()
+				stream.Close()
+				return stats, &scanIncompleteError{reason: fmt.Sprintf("%d skipped messages are held, raise --max-held", len(held))}
+			}
+			if len(held) == 0 {
+				heldSince = time.Now()
+			}
+			held = append(held, &heldMessage{stream: stream, msg: msg})
+			stats.Skipped++
+		}
+	}
+	return stats, nil
+}
+
+// isQueueEmptyErr reports whether a stream receive error is the server error for no message arriving during the wait
+// time, i.e. "no new message in queue, wait time expired"
+func isQueueEmptyErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no new message") || strings.Contains(msg, "wait time expired")
+}
+
+// peekResult is the result of peeking the first messages of a queue
+type peekResult struct {
+	messages []*kubemq2.QueueMessage
+	depth    int
+	limit    int
+	// truncated is true when more messages were requested than the server returns in one request and the queue
+	// holds more messages than were peeked
+	truncated bool
+}
+
+// note returns a description of the messages which were not peeked
+func (p *peekResult) note(channel string) string {
+	return fmt.Sprintf("queue %s holds %d messages, only the first %d were peeked (server max receive messages request)", channel, p.depth, len(p.messages))
+}
+
+// peekQueue returns up to max first messages of a queue without receiving them, all messages when max is 0. A peek
+// always returns the first messages of the queue, so a queue can only be peeked up to limit messages deep, the server
+// max messages of a receive request
+func peekQueue(ctx context.Context, client *kubemq2.Client, channel string, wait, max, limit int) (*peekResult, error) {
+	res := &peekResult{limit: limit}
+	depth, err := queueDepth(ctx, client, channel)
+	if err != nil {
+		if max == 0 {
+			return nil, fmt.Errorf("get queue depth, %s", err.Error())
+		}
+		depth = max
+	}
+	res.depth = depth
+	if max == 0 || max > depth {
+		max = depth
+	}
+	if max == 0 {
+		return res, nil
+	}
+	if max > limit {
+		max = limit
+		res.truncated = true
+	}
+	rqm, err := client.RQM().
+		SetChannel(channel).
+		SetWaitTimeSeconds(wait).
+		SetMaxNumberOfMessages(max).
+		SetIsPeak(true).
+		Send(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("peek queue messages, %s", err.Error())
+	}
+	if rqm.IsError {
+		return nil, fmt.Errorf("peek queue messages, %s", rqm.Error)
+	}
+	res.messages = rqm.Messages
+	return res, nil
+}
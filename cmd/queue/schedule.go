@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s/client"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s/manager/cluster"
+)
+
+// default kubemq cluster queue limits, used when the cluster configuration is not available
+const (
+	defaultMaxDelaySeconds      = 43200
+	defaultMaxExpirationSeconds = 43200
+	defaultMaxReceiveMessages   = 1024
+)
+
+var scheduleTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// queueLimits are the cluster max delay and expiration seconds of queue messages and max messages of a receive request
+type queueLimits struct {
+	maxDelay      int
+	maxExpiration int
+	maxReceive    int
+}
+
+// getQueueLimits reads the queue limits from the current kubemq cluster resource, default limits are returned when
+// the cluster is not managed by kubemqctl or the limits are not set
+func getQueueLimits(cfg *config.Config) *queueLimits {
+	limits := &queueLimits{
+		maxDelay:      defaultMaxDelaySeconds,
+		maxExpiration: defaultMaxExpirationSeconds,
+		maxReceive:    defaultMaxReceiveMessages,
+	}
+	if !cfg.AutoIntegrated {
+		return limits
+	}
+	c, err := client.NewClient(cfg.KubeConfigPath)
+	if err != nil {
+		return limits
+	}
+	clusterManager, err := cluster.NewManager(c)
+	if err != nil {
+		return limits
+	}
+	kc, err := clusterManager.GetCluster(cfg.CurrentStatefulSet, cfg.CurrentNamespace)
+	if err != nil || kc.Spec.Queue == nil {
+		return limits
+	}
+	if kc.Spec.Queue.MaxDelaySeconds != nil && *kc.Spec.Queue.MaxDelaySeconds > 0 {
+		limits.maxDelay = int(*kc.Spec.Queue.MaxDelaySeconds)
+	}
+	if kc.Spec.Queue.MaxExpirationSeconds != nil && *kc.Spec.Queue.MaxExpirationSeconds > 0 {
+		limits.maxExpiration = int(*kc.Spec.Queue.MaxExpirationSeconds)
+	}
+	if kc.Spec.Queue.MaxReceiveMessagesRequest != nil && *kc.Spec.Queue.MaxReceiveMessagesRequest > 0 {
+		limits.maxReceive = int(*kc.Spec.Queue.MaxReceiveMessagesRequest)
+	}
+	return limits
+}
+
+// parseScheduleTime parses an absolute time, RFC3339 times carry their own timezone, other layouts are parsed in loc
+func parseScheduleTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range scheduleTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s, time must be RFC3339 (2006-01-02T15:04:05Z07:00) or 2006-01-02 15:04:05 in --timezone", value)
+}
+
+// scheduleSeconds converts absolute deliver and expire times to policy delay and expiration seconds relative to now,
+// a zero time keeps the relative value
+func scheduleSeconds(now, deliverAt, expireAt time.Time, delay, expiration int, limits *queueLimits) (int, int, error) {
+	if !deliverAt.IsZero() {
+		if !deliverAt.After(now) {
+			return 0, 0, fmt.Errorf("deliver-at %s is in the past", deliverAt.Format(time.RFC3339))
+		}
+		delay = int(math.Ceil(deliverAt.Sub(now).Seconds()))
+		if limits != nil && delay > limits.maxDelay {
+			return 0, 0, fmt.Errorf("deliver-at %s is %d seconds from now, cluster max delay is %d seconds", deliverAt.Format(time.RFC3339), delay, limits.maxDelay)
+		}
+	}
+	if !expireAt.IsZero() {
+		if !expireAt.After(now) {
+			return 0, 0, fmt.Errorf("expire-at %s is in the past", expireAt.Format(time.RFC3339))
+		}
+		if delay > 0 && !expireAt.After(now.Add(time.Duration(delay)*time.Second)) {
+			return 0, 0, fmt.Errorf("expire-at %s is before the message delivery time", expireAt.Format(time.RFC3339))
+		}
+		expiration = int(math.Ceil(expireAt.Sub(now).Seconds()))
+		if limits != nil && expiration > limits.maxExpiration {
+			return 0, 0, fmt.Errorf("expire-at %s is %d seconds from now, cluster max expiration is %d seconds", expireAt.Format(time.RFC3339), expiration, limits.maxExpiration)
+		}
+	}
+	return delay, expiration, nil
+}
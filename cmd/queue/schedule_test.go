@@ -0,0 +1,171 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		value   string
+		loc     *time.Location
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "rfc3339 keeps its own timezone",
+			value: "2026-07-01T12:00:00+02:00",
+			loc:   ny,
+			want:  time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "summer time in dst timezone",
+			value: "2026-07-01 12:00",
+			loc:   ny,
+			want:  time.Date(2026, 7, 1, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "standard time in dst timezone",
+			value: "2026-01-15T12:00:00",
+			loc:   ny,
+			want:  time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "utc",
+			value: "2026-01-15 12:00:30",
+			loc:   time.UTC,
+			want:  time.Date(2026, 1, 15, 12, 0, 30, 0, time.UTC),
+		},
+		{
+			name:    "invalid time",
+			value:   "tomorrow",
+			loc:     time.UTC,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseScheduleTime(tt.value, tt.loc)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestScheduleSeconds(t *testing.T) {
+	now := time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)
+	limits := &queueLimits{maxDelay: 3600, maxExpiration: 7200}
+	tests := []struct {
+		name           string
+		deliverAt      time.Time
+		expireAt       time.Time
+		delay          int
+		expiration     int
+		wantDelay      int
+		wantExpiration int
+		wantErr        string
+	}{
+		{
+			name:           "relative values are kept",
+			delay:          10,
+			expiration:     20,
+			wantDelay:      10,
+			wantExpiration: 20,
+		},
+		{
+			name:      "deliver at rounds up to whole seconds",
+			deliverAt: now.Add(90500 * time.Millisecond),
+			wantDelay: 91,
+		},
+		{
+			// 2026-03-08 is the US spring forward day, 01:00 EST to 03:00 EDT is one hour
+			name:      "deliver at across dst change",
+			deliverAt: time.Date(2026, 3, 8, 3, 0, 0, 0, mustLoadLocation(t, "America/New_York")),
+			wantDelay: 3600,
+		},
+		{
+			name:      "deliver at in the past",
+			deliverAt: now.Add(-time.Second),
+			wantErr:   "is in the past",
+		},
+		{
+			name:      "deliver at now",
+			deliverAt: now,
+			wantErr:   "is in the past",
+		},
+		{
+			name:      "delay exactly at the limit",
+			deliverAt: now.Add(time.Hour),
+			wantDelay: 3600,
+		},
+		{
+			name:      "delay over the limit",
+			deliverAt: now.Add(time.Hour + time.Second),
+			wantErr:   "cluster max delay is 3600 seconds",
+		},
+		{
+			name:           "expiration exactly at the limit",
+			expireAt:       now.Add(2 * time.Hour),
+			wantExpiration: 7200,
+		},
+		{
+			name:     "expiration over the limit",
+			expireAt: now.Add(2*time.Hour + time.Second),
+			wantErr:  "cluster max expiration is 7200 seconds",
+		},
+		{
+			name:     "expire at in the past",
+			expireAt: now.Add(-time.Minute),
+			wantErr:  "is in the past",
+		},
+		{
+			name:      "expire at before delivery",
+			deliverAt: now.Add(time.Hour),
+			expireAt:  now.Add(time.Minute),
+			wantErr:   "before the message delivery time",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, expiration, err := scheduleSeconds(now, tt.deliverAt, tt.expireAt, tt.delay, tt.expiration, limits)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDelay, delay)
+			require.Equal(t, tt.wantExpiration, expiration)
+		})
+	}
+}
+
+func TestGetQueueLimits_Fallback(t *testing.T) {
+	want := &queueLimits{
+		maxDelay:      defaultMaxDelaySeconds,
+		maxExpiration: defaultMaxExpirationSeconds,
+		maxReceive:    defaultMaxReceiveMessages,
+	}
+	require.Equal(t, 43200, want.maxDelay)
+	require.Equal(t, 43200, want.maxExpiration)
+	// the cluster resource is read only for clusters managed by kubemqctl
+	require.Equal(t, want, getQueueLimits(&config.Config{AutoIntegrated: false}))
+	// a cluster resource which cannot be read falls back to the default limits
+	require.Equal(t, want, getQueueLimits(&config.Config{AutoIntegrated: true, KubeConfigPath: "./no-such-kube-config"}))
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
@@ -3,27 +3,45 @@ package queue
 import (
 	"context"
 	"fmt"
+	kubemq2 "github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/targets"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 type QueueSendOptions struct {
-	cfg        *config.Config
-	transport  string
-	expiration int
-	delay      int
-	channel    string
-	body       string
-	maxReceive int
-	metadata   string
-	deadLetter string
-	messages   int
-	fileName   bool
-	build      bool
+	cfg           *config.Config
+	transport     string
+	expiration    int
+	delay         int
+	channel       string
+	body          string
+	maxReceive    int
+	metadata      string
+	deadLetter    string
+	messages      int
+	fileName      bool
+	build         bool
+	noTemplate    bool
+	fromFile      string
+	csvMap        map[string]string
+	batchSize     int
+	deliverAt     string
+	expireAt      string
+	timezone      string
+	location      *time.Location
+	deliverTime   time.Time
+	expireTime    time.Time
+	limits        *queueLimits
+	schemaOptions schema.Options
+	stdin         bool
+	message       *generator.Message
 }
 
 var queueSendExamples = `
@@ -42,8 +60,32 @@ var queueSendExamples = `
 	# Send message to a queue channel with a message delay of 5 seconds
 	kubemqctl queue send q1 some-message -d 5
 
+	# Send message to a queue channel delivered at 09:00 UTC and expired at 10:00 UTC
+	kubemqctl queue send q1 some-message --deliver-at 2026-10-20T09:00:00Z --expire-at 2026-10-20T10:00:00Z
+
+	# Send message to a queue channel delivered at 09:00 Berlin time
+	kubemqctl queue send q1 some-message --deliver-at '2026-10-20 09:00' --timezone Europe/Berlin
+
 	# Send message to a queue channel with a message policy of max receive 5 times and dead-letter queue 'dead-letter'
 	kubemqctl queue send q1 some-message -r 5 -q dead-letter
+
+	# Send messages from a ndjson file, each line may set body, metadata, tags, expiration, delay, deliver_at, expire_at, max_receive and dead_letter_queue
+	kubemqctl queue send q1 --from-file messages.ndjson
+
+	# Send messages from a csv file mapping 'payload' column to body and 'kind' column to tag 'type'
+	kubemqctl queue send q1 --from-file messages.csv --csv-map body=payload,tag.type=kind
+
+	# Send a message to a queue channel q1 after validating the body against a JSON Schema
+	kubemqctl queue send q1 '{"id":"o-1","amount":10}' --schema order.schema.json
+
+	# Send 100 messages to a queue channel with templated body, functions are uuid, now, seq, randInt, randChoice, env and file
+	kubemqctl queue send q1 '{"id":"{{uuid}}","n":{{seq}},"amount":{{randInt 1 100}}}' -m 100
+
+	# Send a body containing literal {{ }} braces to a queue channel without templating
+	kubemqctl queue send q1 'Hello {{ .Name }}' --no-template
+
+	# Send each line of stdin as a message body to a queue channel
+	cat jobs.txt | kubemqctl queue send q1 -
 `
 var queueSendLong = `Send command allows to send one or many message to a queue channel`
 var queueSendShort = `Send a message to a queue channel command`
@@ -76,6 +118,14 @@ func NewCmdQueueSend(ctx context.Context, cfg *config.Config) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&o.metadata, "metadata", "", "", "set queue message metadata field")
 	cmd.PersistentFlags().BoolVarP(&o.fileName, "file", "f", false, "set load message body from file")
 	cmd.PersistentFlags().BoolVarP(&o.build, "build", "b", false, "build kubemq targets request")
+	cmd.PersistentFlags().BoolVarP(&o.noTemplate, "no-template", "", false, "set send body and metadata as is without rendering {{ }} template actions")
+	cmd.PersistentFlags().StringVarP(&o.fromFile, "from-file", "", "", "set send messages from ndjson (.ndjson, .gz) or csv (.csv) file")
+	cmd.PersistentFlags().StringToStringVarP(&o.csvMap, "csv-map", "", map[string]string{}, "set csv column mapping field=column, fields: id, channel, metadata, body, body_base64, expiration, delay, deliver_at, expire_at, max_receive, dead_letter_queue, tag.<key>")
+	cmd.PersistentFlags().IntVarP(&o.batchSize, "batch-size", "", 100, "set how many messages to send in each batch request (from-file mode only)")
+	cmd.PersistentFlags().StringVarP(&o.deliverAt, "deliver-at", "", "", "set queue message delivery time, i.e. 2026-10-20T09:00:00Z or '2026-10-20 09:00' in --timezone")
+	cmd.PersistentFlags().StringVarP(&o.expireAt, "expire-at", "", "", "set queue message expiration time, i.e. 2026-10-20T10:00:00Z or '2026-10-20 10:00' in --timezone")
+	cmd.PersistentFlags().StringVarP(&o.timezone, "timezone", "", "Local", "set timezone of deliver-at and expire-at times without offset, i.e. UTC, Europe/Berlin")
+	o.schemaOptions.AddFlags(cmd)
 
 	return cmd
 }
@@ -88,6 +138,23 @@ func (o *QueueSendOptions) Complete(args []string, transport string) error {
 	} else {
 		return fmt.Errorf("missing channel argument")
 	}
+	var err error
+	if o.location, err = time.LoadLocation(o.timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s, %s", o.timezone, err.Error())
+	}
+	if o.deliverAt != "" {
+		if o.deliverTime, err = parseScheduleTime(o.deliverAt, o.location); err != nil {
+			return err
+		}
+	}
+	if o.expireAt != "" {
+		if o.expireTime, err = parseScheduleTime(o.expireAt, o.location); err != nil {
+			return err
+		}
+	}
+	if o.fromFile != "" {
+		return nil
+	}
 	if o.build {
 		data, err := targets.BuildRequest()
 		if err != nil {
@@ -105,6 +172,7 @@ func (o *QueueSendOptions) Complete(args []string, transport string) error {
 	} else {
 		if len(args) >= 2 {
 			o.body = args[1]
+			o.stdin = o.body == "-"
 		} else {
 			return fmt.Errorf("missing body argument")
 		}
@@ -114,7 +182,46 @@ func (o *QueueSendOptions) Complete(args []string, transport string) error {
 }
 
 func (o *QueueSendOptions) Validate() error {
-	return nil
+	if o.fromFile != "" && o.batchSize <= 0 {
+		return fmt.Errorf("batch size must be a positive number")
+	}
+	if err := validateQueueSendCSVMap(o.csvMap); err != nil {
+		return err
+	}
+	if o.deliverAt != "" && o.delay > 0 {
+		return fmt.Errorf("deliver-at and delay cannot be set together")
+	}
+	if o.expireAt != "" && o.expiration > 0 {
+		return fmt.Errorf("expire-at and expiration cannot be set together")
+	}
+	// messages from file are validated per line by their channel and stdin lines are validated as they arrive
+	if o.fromFile != "" || o.stdin {
+		return nil
+	}
+	var err error
+	if o.message, err = generator.NewMessage(o.body, o.metadata, !o.noTemplate && !o.fileName && !o.build, !o.noTemplate); err != nil {
+		return err
+	}
+	// templated bodies are validated per message, the first message is validated before connecting
+	body, _, err := o.message.Render(1)
+	if err != nil {
+		return err
+	}
+	return o.schemaOptions.ValidateBody(o.cfg, o.channel, body)
+}
+
+// render returns the body and metadata of message seq, templated bodies are validated against the channel schema
+func (o *QueueSendOptions) render(seq int) ([]byte, string, error) {
+	body, metadata, err := o.message.Render(seq)
+	if err != nil {
+		return nil, "", err
+	}
+	if !o.message.IsStatic() {
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, body); err != nil {
+			return nil, "", fmt.Errorf("message %d, %s", seq, err.Error())
+		}
+	}
+	return body, metadata, nil
 }
 
 func (o *QueueSendOptions) Run(ctx context.Context) error {
@@ -126,14 +233,31 @@ func (o *QueueSendOptions) Run(ctx context.Context) error {
 	defer func() {
 		client.Close()
 	}()
+	if o.fromFile != "" {
+		return o.runFromFile(ctx, client)
+	}
+	if o.stdin {
+		return o.runStdin(ctx, client)
+	}
 
 	for i := 0; i < o.messages; i++ {
+		delay, expiration := o.delay, o.expiration
+		if !o.deliverTime.IsZero() || !o.expireTime.IsZero() {
+			delay, expiration, err = scheduleSeconds(time.Now(), o.deliverTime, o.expireTime, delay, expiration, o.queueLimits())
+			if err != nil {
+				return err
+			}
+		}
+		body, metadata, err := o.render(i + 1)
+		if err != nil {
+			return err
+		}
 		msg := client.QM().
 			SetChannel(o.channel).
-			SetBody([]byte(o.body)).
-			SetMetadata(o.metadata).
-			SetPolicyExpirationSeconds(o.expiration).
-			SetPolicyDelaySeconds(o.delay).
+			SetBody(body).
+			SetMetadata(metadata).
+			SetPolicyExpirationSeconds(expiration).
+			SetPolicyDelaySeconds(delay).
 			SetPolicyMaxReceiveCount(o.maxReceive).
 			SetPolicyMaxReceiveQueue(o.deadLetter)
 		utils.Println("Sending Queue Message:")
@@ -150,3 +274,65 @@ func (o *QueueSendOptions) Run(ctx context.Context) error {
 
 	return nil
 }
+
+func (o *QueueSendOptions) runFromFile(ctx context.Context, client *kubemq2.Client) error {
+	utils.Printlnf("sending queue messages from %s...", o.fromFile)
+	sent := 0
+	var failed []string
+	var batch []*kubemq2.QueueMessage
+	var lines []int
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		results, err := client.SendQueueMessages(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("error sending queue messages batch, %s", err.Error())
+		}
+		for i, res := range results {
+			if res.IsError {
+				failed = append(failed, fmt.Sprintf("line %d: %s", lines[i], res.Error))
+				continue
+			}
+			sent++
+		}
+		utils.Printlnf("sent %d messages, %d failed", sent, len(failed))
+		batch, lines = nil, nil
+		return nil
+	}
+	err := readQueueSendRecords(o.fromFile, o.csvMap, func(rec *queueSendRecord) error {
+		msg, err := rec.toQueueMessage(client, o)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("line %d: %s", rec.line, err.Error()))
+			return nil
+		}
+		batch = append(batch, msg)
+		lines = append(lines, rec.line)
+		if len(batch) >= o.batchSize {
+			return send()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := send(); err != nil {
+		return err
+	}
+	utils.Printlnf("Summary: %d messages sent, %d failed", sent, len(failed))
+	for _, f := range failed {
+		utils.PrintlnfNoTitle("  %s", f)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d messages failed to send", len(failed))
+	}
+	return nil
+}
+
+// queueLimits loads the cluster queue limits once, they are needed only for absolute deliver and expire times
+func (o *QueueSendOptions) queueLimits() *queueLimits {
+	if o.limits == nil {
+		o.limits = getQueueLimits(o.cfg)
+	}
+	return o.limits
+}
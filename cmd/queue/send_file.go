@@ -0,0 +1,290 @@
+package queue
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/archive"
+)
+
+// queueSendRecord is a single message line of a bulk send file, missing fields are taken from the send command flags
+type queueSendRecord struct {
+	Channel         string            `json:"channel,omitempty"`
+	Id              string            `json:"id,omitempty"`
+	Metadata        *string           `json:"metadata,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	Body            json.RawMessage   `json:"body,omitempty"`
+	BodyBase64      string            `json:"body_base64,omitempty"`
+	Expiration      *int              `json:"expiration,omitempty"`
+	Delay           *int              `json:"delay,omitempty"`
+	DeliverAt       *string           `json:"deliver_at,omitempty"`
+	ExpireAt        *string           `json:"expire_at,omitempty"`
+	MaxReceive      *int              `json:"max_receive,omitempty"`
+	DeadLetterQueue *string           `json:"dead_letter_queue,omitempty"`
+	line            int
+}
+
+func (r *queueSendRecord) body() ([]byte, error) {
+	if r.BodyBase64 != "" {
+		data, err := b64.StdEncoding.DecodeString(r.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body_base64, %s", err.Error())
+		}
+		return data, nil
+	}
+	raw := bytes.TrimSpace(r.Body)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	// a json string body is sent as its content, any other json value is sent as is
+	if raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, err
+		}
+		return []byte(str), nil
+	}
+	return raw, nil
+}
+
+func (r *queueSendRecord) toQueueMessage(client *kubemq2.Client, o *QueueSendOptions) (*kubemq2.QueueMessage, error) {
+	body, err := r.body()
+	if err != nil {
+		return nil, err
+	}
+	channel := o.channel
+	if r.Channel != "" {
+		channel = r.Channel
+	}
+	if err := o.schemaOptions.ValidateBody(o.cfg, channel, body); err != nil {
+		return nil, err
+	}
+	metadata := o.metadata
+	if r.Metadata != nil {
+		metadata = *r.Metadata
+	}
+	expiration, delay, maxReceive, deadLetter := o.expiration, o.delay, o.maxReceive, o.deadLetter
+	deliverAt, expireAt := o.deliverTime, o.expireTime
+	if r.Expiration != nil {
+		expiration = *r.Expiration
+		expireAt = time.Time{}
+	}
+	if r.Delay != nil {
+		delay = *r.Delay
+		deliverAt = time.Time{}
+	}
+	if r.DeliverAt != nil {
+		if r.Delay != nil {
+			return nil, fmt.Errorf("deliver_at and delay cannot be set together")
+		}
+		if deliverAt, err = parseScheduleTime(*r.DeliverAt, o.location); err != nil {
+			return nil, err
+		}
+	}
+	if r.ExpireAt != nil {
+		if r.Expiration != nil {
+			return nil, fmt.Errorf("expire_at and expiration cannot be set together")
+		}
+		if expireAt, err = parseScheduleTime(*r.ExpireAt, o.location); err != nil {
+			return nil, err
+		}
+	}
+	if !deliverAt.IsZero() || !expireAt.IsZero() {
+		delay, expiration, err = scheduleSeconds(time.Now(), deliverAt, expireAt, delay, expiration, o.queueLimits())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.MaxReceive != nil {
+		maxReceive = *r.MaxReceive
+	}
+	if r.DeadLetterQueue != nil {
+		deadLetter = *r.DeadLetterQueue
+	}
+	return client.QM().
+		SetId(r.Id).
+		SetChannel(channel).
+		SetMetadata(metadata).
+		SetTags(r.Tags).
+		SetBody(body).
+		SetPolicyExpirationSeconds(expiration).
+		SetPolicyDelaySeconds(delay).
+		SetPolicyMaxReceiveCount(maxReceive).
+		SetPolicyMaxReceiveQueue(deadLetter), nil
+}
+
+// readQueueSendRecords calls fn for each record of a ndjson (optionally gzip compressed) or csv file
+func readQueueSendRecords(fileName string, csvMap map[string]string, fn func(rec *queueSendRecord) error) error {
+	if strings.HasSuffix(strings.ToLower(fileName), ".csv") {
+		return readQueueSendCSV(fileName, csvMap, fn)
+	}
+	r, err := archive.NewReader(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	for {
+		rec := &queueSendRecord{}
+		err := r.Next(rec)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rec.line = r.Line()
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+var queueSendCSVFields = []string{"id", "channel", "metadata", "body", "body_base64", "expiration", "delay", "deliver_at", "expire_at", "max_receive", "dead_letter_queue"}
+
+// validateQueueSendCSVMap returns an error for csv map fields which are not record fields or tag.<key>
+func validateQueueSendCSVMap(csvMap map[string]string) error {
+	for field := range csvMap {
+		if strings.HasPrefix(field, "tag.") && len(field) > len("tag.") {
+			continue
+		}
+		known := false
+		for _, f := range queueSendCSVFields {
+			if f == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("invalid csv map field %s, fields are %s, tag.<key>", field, strings.Join(queueSendCSVFields, ", "))
+		}
+	}
+	return nil
+}
+
+// readQueueSendCSV reads a csv file with a header row, csvMap maps record fields (and tag.<key>) to csv column names,
+// columns that are not mapped are matched by their header name. csvMap fields are validated by validateQueueSendCSVMap
+func readQueueSendCSV(fileName string, csvMap map[string]string, fn func(rec *queueSendRecord) error) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("open csv file, %s", err.Error())
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("read csv header, %s", err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	fields := map[string]int{}
+	for _, field := range queueSendCSVFields {
+		if idx, ok := columns[field]; ok {
+			fields[field] = idx
+		}
+	}
+	for name, idx := range columns {
+		if strings.HasPrefix(name, "tag.") {
+			fields[name] = idx
+		}
+	}
+	for field, column := range csvMap {
+		idx, ok := columns[column]
+		if !ok {
+			return fmt.Errorf("csv column %s mapped to %s was not found in header", column, field)
+		}
+		fields[field] = idx
+	}
+	if _, ok := fields["body"]; !ok {
+		if _, ok := fields["body_base64"]; !ok {
+			return fmt.Errorf("csv file has no body or body_base64 column")
+		}
+	}
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		line++
+		if err != nil {
+			return fmt.Errorf("read csv line %d, %s", line, err.Error())
+		}
+		rec, err := newQueueSendRecordFromCSV(row, fields)
+		if err != nil {
+			return fmt.Errorf("invalid csv line %d, %s", line, err.Error())
+		}
+		rec.line = line
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+func newQueueSendRecordFromCSV(row []string, fields map[string]int) (*queueSendRecord, error) {
+	rec := &queueSendRecord{}
+	for field, idx := range fields {
+		if idx >= len(row) || row[idx] == "" {
+			continue
+		}
+		value := row[idx]
+		switch field {
+		case "id":
+			rec.Id = value
+		case "channel":
+			rec.Channel = value
+		case "metadata":
+			rec.Metadata = &value
+		case "body":
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			rec.Body = data
+		case "body_base64":
+			rec.BodyBase64 = value
+		case "dead_letter_queue":
+			rec.DeadLetterQueue = &value
+		case "deliver_at":
+			rec.DeliverAt = &value
+		case "expire_at":
+			rec.ExpireAt = &value
+		case "expiration", "delay", "max_receive":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %s", field, value)
+			}
+			switch field {
+			case "expiration":
+				rec.Expiration = &n
+			case "delay":
+				rec.Delay = &n
+			default:
+				rec.MaxReceive = &n
+			}
+		default:
+			if strings.HasPrefix(field, "tag.") {
+				if rec.Tags == nil {
+					rec.Tags = map[string]string{}
+				}
+				rec.Tags[strings.TrimPrefix(field, "tag.")] = value
+			}
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// runStdin sends each stdin line as a queue message body as it arrives and reports the counts on EOF
+func (o *QueueSendOptions) runStdin(ctx context.Context, client *kubemq2.Client) error {
+	linesCh, readErrCh := utils.ReadLines(ctx, os.Stdin)
+	utils.Printlnf("sending stdin lines to queue %s...", o.channel)
+	sent, failed, skipped, line := 0, 0, 0, 0
+	summary := func() {
+		utils.Printlnf("Summary: %d messages sent, %d failed, %d invalid lines skipped", sent, failed, skipped)
+	}
+	for body := range linesCh {
+		line++
+		if err := o.schemaOptions.ValidateBody(o.cfg, o.channel, []byte(body)); err != nil {
+			utils.Printlnf("line %d skipped, %s", line, err.Error())
+			skipped++
+			continue
+		}
+		delay, expiration := o.delay, o.expiration
+		if !o.deliverTime.IsZero() || !o.expireTime.IsZero() {
+			var err error
+			delay, expiration, err = scheduleSeconds(time.Now(), o.deliverTime, o.expireTime, delay, expiration, o.queueLimits())
+			if err != nil {
+				summary()
+				return err
+			}
+		}
+		res, err := client.QM().
+			SetChannel(o.channel).
+			SetBody([]byte(body)).
+			SetMetadata(o.metadata).
+			SetPolicyExpirationSeconds(expiration).
+			SetPolicyDelaySeconds(delay).
+			SetPolicyMaxReceiveCount(o.maxReceive).
+			SetPolicyMaxReceiveQueue(o.deadLetter).
+			Send(ctx)
+		if err != nil {
+			summary()
+			return fmt.Errorf("error sending queue message, %s", err.Error())
+		}
+		if res.IsError {
+			utils.Printlnf("line %d failed, %s", line, res.Error)
+			failed++
+			continue
+		}
+		sent++
+	}
+	summary()
+	select {
+	case err := <-readErrCh:
+		return fmt.Errorf("read stdin, %s", err.Error())
+	default:
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d messages failed to send", failed)
+	}
+	return nil
+}
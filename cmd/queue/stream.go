@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/decoder"
 	"github.com/kubemq-io/kubemqctl/pkg/k8s"
 	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"strconv"
@@ -14,11 +16,20 @@ import (
 )
 
 type QueueStreamOptions struct {
-	cfg        *config.Config
-	transport  string
-	channel    string
-	visibility int
-	wait       int
+	cfg           *config.Config
+	transport     string
+	channel       string
+	visibility    int
+	wait          int
+	exec          string
+	execTimeout   int
+	concurrency   int
+	maxExtends    int
+	exitWhenEmpty bool
+	decodeOptions decoder.Options
+	decoder       decoder.Decoder
+	schemaOptions schema.Options
+	validator     *schema.Validator
 }
 
 var queueStreamExamples = `
@@ -27,8 +38,20 @@ var queueStreamExamples = `
 
 	# Stream 'queues' message in transaction mode with visibility set to 120 seconds and wait time of 180 seconds
 	kubemqctl queue stream q1 -v 120 -w 180
+
+	# Stream 'queues' messages to a handler script, exit code 0 acks the message, any other exit code rejects it
+	kubemqctl queue stream q1 --exec ./handler.sh
+
+	# Stream 'queues' messages to a handler with 4 workers and exit when the queue is empty
+	kubemqctl queue stream q1 --exec 'python3 handler.py' --concurrency 4 --exit-when-empty
+
+	# Stream 'queues' messages with avro bodies decoded by a schema file
+	kubemqctl queue stream q1 --decode avro --avro-schema order.avsc
 `
-var queueStreamLong = `Stream command allows to receive message from a queue in push mode response an appropriate action`
+var queueStreamLong = `Stream command allows to receive message from a queue in push mode response an appropriate action.
+In exec mode each message is passed as json to the handler stdin, the handler can reply on stdout with json
+{"action":"ack|reject|extend|resend","visibility":60,"channel":"other-queue"}, otherwise the exit code decides (0 ack, else reject).
+An extend reply requires a positive visibility and runs the handler again, a message extended more than max extends times is rejected`
 var queueStreamShort = `Stream a message from a queue command`
 
 func NewCmdQueueStream(ctx context.Context, cfg *config.Config) *cobra.Command {
@@ -53,7 +76,14 @@ func NewCmdQueueStream(ctx context.Context, cfg *config.Config) *cobra.Command {
 	}
 	cmd.PersistentFlags().IntVarP(&o.visibility, "visibility", "v", 30, "set initial visibility seconds")
 	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 60, "set how many seconds to wait for 'queues' messages")
+	cmd.PersistentFlags().StringVarP(&o.exec, "exec", "", "", "set handler command to run for each message (non-interactive mode)")
+	cmd.PersistentFlags().IntVarP(&o.execTimeout, "exec-timeout", "", 0, "set handler command timeout seconds, 0 is no timeout")
+	cmd.PersistentFlags().IntVarP(&o.concurrency, "concurrency", "", 1, "set how many workers run the handler concurrently")
+	cmd.PersistentFlags().IntVarP(&o.maxExtends, "max-extends", "", 5, "set how many times a handler can extend a message visibility before the message is rejected (exec mode only)")
+	cmd.PersistentFlags().BoolVarP(&o.exitWhenEmpty, "exit-when-empty", "", false, "set exit when no message arrives during wait time (exec mode only)")
+	o.decodeOptions.AddFlags(cmd)
 
+	o.schemaOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -61,12 +91,29 @@ func (o *QueueStreamOptions) Complete(args []string, transport string) error {
 	o.transport = transport
 	if len(args) >= 1 {
 		o.channel = args[0]
-		return nil
+	} else {
+		return fmt.Errorf("missing channel argument")
 	}
-	return fmt.Errorf("missing channel argument")
+	var err error
+	if o.decoder, err = o.decodeOptions.Decoder(); err != nil {
+		return err
+	}
+	if o.validator, err = o.schemaOptions.Validator(o.cfg, o.channel); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (o *QueueStreamOptions) Validate() error {
+	if o.exec == "" && (o.concurrency != 1 || o.exitWhenEmpty) {
+		return fmt.Errorf("concurrency and exit-when-empty require exec mode")
+	}
+	if o.concurrency <= 0 {
+		return fmt.Errorf("concurrency must be a positive number")
+	}
+	if o.maxExtends < 0 {
+		return fmt.Errorf("max-extends must be zero or a positive number")
+	}
 	return nil
 }
 
@@ -79,6 +126,9 @@ func (o *QueueStreamOptions) Run(ctx context.Context) error {
 	defer func() {
 		client.Close()
 	}()
+	if o.exec != "" {
+		return o.runExec(ctx, client)
+	}
 
 	for {
 		stream := client.NewStreamQueueMessage().SetChannel(o.channel)
@@ -87,7 +137,10 @@ func (o *QueueStreamOptions) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		utils.Printlnf("[channel: %s] [client id: %s] -> {id: %s, metadata: %s, body: %s}", msg.Channel, msg.ClientID, msg.MessageID, msg.Metadata, msg.Body)
+		utils.Printlnf("[channel: %s] [client id: %s] -> {id: %s, metadata: %s, body: %s}", msg.Channel, msg.ClientID, msg.MessageID, msg.Metadata, decoder.String(o.decoder, msg.Body))
+		if obj := newQueueMessageObject(msg, o.decoder).withSchema(o.validator); obj.SchemaError != "" {
+			utils.Printlnf("schema error: %s", obj.SchemaError)
+		}
 	PROMPT:
 		action, result, err := o.prompt()
 		if err != nil {
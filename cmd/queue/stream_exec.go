@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+)
+
+// streamExecReply is the optional json reply a handler can print to stdout to select the message action
+type streamExecReply struct {
+	Action     string `json:"action"`
+	Visibility int    `json:"visibility,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+}
+
+func (o *QueueStreamOptions) runExec(ctx context.Context, client *kubemq2.Client) error {
+	utils.Printlnf("streaming messages from %s to '%s' with %d workers...", o.channel, o.exec, o.concurrency)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, o.concurrency)
+	wg := sync.WaitGroup{}
+	for i := 1; i <= o.concurrency; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if err := o.execWorker(ctx, client, id); err != nil {
+				errCh <- err
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+func (o *QueueStreamOptions) execWorker(ctx context.Context, client *kubemq2.Client, id int) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		stream := client.NewStreamQueueMessage().SetChannel(o.channel)
+		msg, err := stream.Next(ctx, int32(o.visibility), int32(o.wait))
+		if err != nil {
+			stream.Close()
+			if isQueueEmptyErr(err) {
+				if o.exitWhenEmpty {
+					utils.Printlnf("[worker %d] queue %s is empty, exiting", id, o.channel)
+					return nil
+				}
+				continue
+			}
+			return err
+		}
+		if msg == nil {
+			stream.Close()
+			return nil
+		}
+		if err := o.execMessage(ctx, id, msg); err != nil {
+			stream.Close()
+			return err
+		}
+	}
+}
+
+// execMessage runs the handler until it acks, rejects or resends the message, extend replies run the handler again up
+// to max extends times and then the message is rejected
+func (o *QueueStreamOptions) execMessage(ctx context.Context, id int, msg *kubemq2.QueueMessage) error {
+	for extends := 0; ; extends++ {
+		reply, err := o.execHandler(ctx, msg)
+		if err != nil {
+			return err
+		}
+		if reply.Action == "extend" && extends >= o.maxExtends {
+			utils.Printlnf("[worker %d] message %s visibility was extended %d times, rejecting message", id, msg.MessageID, extends)
+			reply.Action = "reject"
+		}
+		switch reply.Action {
+		case "ack":
+			if err := msg.Ack(); err != nil {
+				return err
+			}
+			utils.Printlnf("[worker %d] message %s acked", id, msg.MessageID)
+			return nil
+		case "reject":
+			if err := msg.Reject(); err != nil {
+				return err
+			}
+			utils.Printlnf("[worker %d] message %s rejected", id, msg.MessageID)
+			return nil
+		case "extend":
+			if err := msg.ExtendVisibility(int32(reply.Visibility)); err != nil {
+				return err
+			}
+			utils.Printlnf("[worker %d] message %s visibility extended by %d seconds, running handler again", id, msg.MessageID, reply.Visibility)
+		case "resend":
+			if err := msg.Resend(reply.Channel); err != nil {
+				return err
+			}
+			utils.Printlnf("[worker %d] message %s resent to %s", id, msg.MessageID, reply.Channel)
+			return nil
+		}
+	}
+}
+
+// execHandler runs the handler with the message as json on stdin. A valid json reply on stdout decides the action,
+// otherwise exit code 0 acks the message and any other exit code rejects it
+func (o *QueueStreamOptions) execHandler(ctx context.Context, msg *kubemq2.QueueMessage) (*streamExecReply, error) {
+	input, err := json.Marshal(newQueueMessageObject(msg, o.decoder).withSchema(o.validator))
+	if err != nil {
+		return nil, err
+	}
+	if o.execTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(o.execTimeout)*time.Second)
+		defer cancel()
+	}
+	cmd := utils.ShellCommand(ctx, o.exec)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"KUBEMQ_CHANNEL="+msg.Channel,
+		"KUBEMQ_MESSAGE_ID="+msg.MessageID,
+		"KUBEMQ_METADATA="+msg.Metadata,
+	)
+	if msg.Attributes != nil {
+		cmd.Env = append(cmd.Env,
+			"KUBEMQ_SEQUENCE="+strconv.FormatUint(msg.Attributes.Sequence, 10),
+			"KUBEMQ_RECEIVE_COUNT="+strconv.Itoa(int(msg.Attributes.ReceiveCount)),
+		)
+	}
+	out, runErr := cmd.Output()
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("run handler '%s', %s", o.exec, runErr.Error())
+	}
+	reply := &streamExecReply{}
+	if trimmed := bytes.TrimSpace(out); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, reply); err == nil {
+			if err := reply.normalize(); err != nil {
+				utils.Printlnf("invalid handler reply for message %s, %s, rejecting message", msg.MessageID, err.Error())
+				return &streamExecReply{Action: "reject"}, nil
+			}
+			return reply, nil
+		}
+	}
+	if len(out) > 0 {
+		fmt.Print(string(out))
+	}
+	if runErr != nil {
+		reply.Action = "reject"
+	} else {
+		reply.Action = "ack"
+	}
+	return reply, nil
+}
+
+func (r *streamExecReply) normalize() error {
+	r.Action = strings.ToLower(strings.TrimSpace(r.Action))
+	switch r.Action {
+	case "ack", "reject":
+	case "extend", "extend_visibility", "extendvisibility":
+		r.Action = "extend"
+		if r.Visibility <= 0 {
+			return fmt.Errorf("extend reply visibility must be a positive number of seconds")
+		}
+	case "resend":
+		if r.Channel == "" {
+			return fmt.Errorf("resend reply is missing a channel")
+		}
+	default:
+		return fmt.Errorf("unknown action '%s'", r.Action)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/k8s"
+	"github.com/kubemq-io/kubemqctl/pkg/kubemq"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type QueueTransferOptions struct {
+	cfg           *config.Config
+	transport     string
+	isCopy        bool
+	src           string
+	dst           string
+	dstContext    string
+	max           int
+	filterExpr    string
+	metadataRegex string
+	bodyRegex     string
+	tags          []string
+	dryRun        bool
+	visibility    int
+	maxHeld       int
+	batchSize     int
+	wait          int
+	filter        *messageFilter
+}
+
+var queueMoveExamples = `
+	# Move all messages from queue q1 to queue q2
+	kubemqctl queue move q1 q2
+
+	# Move up to 100 messages with metadata or body matching 'order-.*' from queue q1 to queue q2
+	kubemqctl queue move q1 q2 --max 100 --filter 'order-.*'
+
+	# Show which messages would be moved from queue q1 to queue q2 without moving them
+	kubemqctl queue move q1 q2 --filter 'order-.*' --dry-run
+
+	# Move all messages from queue q1 to queue q1 in another cluster set by a kubemqctl configuration file
+	kubemqctl queue move q1 q1 --dst-context ./other-cluster.yaml
+`
+var queueMoveLong = `Move command allows to transfer messages between queue channels in transaction mode, messages are removed from the source queue only when sent to the destination queue`
+var queueMoveShort = `Move messages between queue channels command`
+
+var queueCopyExamples = `
+	# Copy all messages from queue q1 to queue q2
+	kubemqctl queue copy q1 q2
+
+	# Copy up to 100 messages with tag 'type' equal to 'payment' from queue q1 to queue q2
+	kubemqctl queue copy q1 q2 --max 100 --tag 'type=^payment$'
+
+	# Copy all messages from queue q1 to queue q1 in another cluster set by a kubemqctl configuration file
+	kubemqctl queue copy q1 q1 --dst-context ./other-cluster.yaml
+`
+var queueCopyLong = `Copy command allows to copy messages from a queue channel into another queue channel, the source queue is not changed.
+Messages are peeked, so only the first messages of the source queue up to the server max receive messages request (default 1024) can be copied`
+var queueCopyShort = `Copy messages between queue channels command`
+
+func NewCmdQueueMove(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueTransferOptions{
+		cfg: cfg,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "move",
+		Aliases: []string{"mv"},
+		Short:   queueMoveShort,
+		Long:    queueMoveLong,
+		Example: queueMoveExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	o.addFlags(cmd)
+	cmd.PersistentFlags().IntVarP(&o.visibility, "visibility", "v", 300, "set visibility seconds for messages held during move")
+	cmd.PersistentFlags().IntVarP(&o.maxHeld, "max-held", "", defaultMaxHeld, "set max skipped messages held invisible during move, each one keeps a stream open")
+	return cmd
+}
+
+func NewCmdQueueCopy(ctx context.Context, cfg *config.Config) *cobra.Command {
+	o := &QueueTransferOptions{
+		cfg:    cfg,
+		isCopy: true,
+	}
+	cmd := &cobra.Command{
+
+		Use:     "copy",
+		Aliases: []string{"cp"},
+		Short:   queueCopyShort,
+		Long:    queueCopyLong,
+		Example: queueCopyExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			utils.CheckErr(o.Complete(args, cfg.ConnectionType), cmd)
+			utils.CheckErr(o.Validate())
+			utils.CheckErr(k8s.SetTransport(ctx, cfg))
+			utils.CheckErr(o.Run(ctx))
+		},
+	}
+	o.addFlags(cmd)
+	cmd.PersistentFlags().IntVarP(&o.batchSize, "batch-size", "", 100, "set how many messages to send to the destination queue in each request")
+	return cmd
+}
+
+func (o *QueueTransferOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.dstContext, "dst-context", "", "", "set kubemqctl configuration file of the destination cluster")
+	cmd.PersistentFlags().IntVarP(&o.max, "max", "m", 0, "set max messages to transfer, 0 transfers all messages")
+	cmd.PersistentFlags().StringVarP(&o.filterExpr, "filter", "", "", "set transfer only messages with metadata or body matching regex")
+	cmd.PersistentFlags().StringVarP(&o.metadataRegex, "metadata-regex", "", "", "set transfer only messages with metadata matching regex")
+	cmd.PersistentFlags().StringVarP(&o.bodyRegex, "body-regex", "", "", "set transfer only messages with body matching regex")
+	cmd.PersistentFlags().StringArrayVarP(&o.tags, "tag", "", []string{}, "set transfer only messages with tag matching key=regex")
+	cmd.PersistentFlags().BoolVarP(&o.dryRun, "dry-run", "", false, "set list matching messages without transferring them")
+	cmd.PersistentFlags().IntVarP(&o.wait, "wait", "w", 2, "set how many seconds to wait for source queue messages")
+}
+
+func (o *QueueTransferOptions) Complete(args []string, transport string) error {
+	o.transport = transport
+	if len(args) >= 2 {
+		o.src = args[0]
+		o.dst = args[1]
+	} else {
+		return fmt.Errorf("missing source and destination channel arguments")
+	}
+	var err error
+	o.filter, err = newMessageFilter(o.metadataRegex, o.bodyRegex, o.tags)
+	if err != nil {
+		return err
+	}
+	return o.filter.withAny(o.filterExpr)
+}
+
+func (o *QueueTransferOptions) Validate() error {
+	if o.src == o.dst && o.dstContext == "" {
+		return fmt.Errorf("source and destination queues must be different")
+	}
+	if o.max < 0 {
+		return fmt.Errorf("max must be zero or a positive number")
+	}
+	if o.isCopy && o.batchSize <= 0 {
+		return fmt.Errorf("batch size must be a positive number")
+	}
+	if !o.isCopy {
+		if err := validateScan(o.visibility, o.wait, o.maxHeld); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *QueueTransferOptions) Run(ctx context.Context) error {
+	client, err := kubemq.GetKubemqClient(ctx, o.transport, o.cfg)
+	if err != nil {
+		return fmt.Errorf("create kubemq client, %s", err.Error())
+
+	}
+	defer func() {
+		client.Close()
+	}()
+	dstClient := client
+	if o.dstContext != "" && !o.dryRun {
+		dstCfg, err := config.LoadConfig(o.dstContext)
+		if err != nil {
+			return err
+		}
+		if err := k8s.SetTransport(ctx, dstCfg); err != nil {
+			return err
+		}
+		dstClient, err = kubemq.GetKubemqClient(ctx, dstCfg.ConnectionType, dstCfg)
+		if err != nil {
+			return fmt.Errorf("create destination kubemq client, %s", err.Error())
+		}
+		defer func() {
+			dstClient.Close()
+		}()
+	}
+	if o.isCopy || o.dryRun {
+		return o.runPeek(ctx, client, dstClient)
+	}
+	return o.runMove(ctx, client, dstClient)
+}
+
+// runPeek copies matching messages without changing the source queue, in dry-run mode matching messages are only listed.
+// Messages are peeked up to the server max receive messages request and sent in batches of batch size
+func (o *QueueTransferOptions) runPeek(ctx context.Context, client, dstClient *kubemq2.Client) error {
+	limits := getQueueLimits(o.cfg)
+	peeked, err := peekQueue(ctx, client, o.src, o.wait, o.max, limits.maxReceive)
+	if err != nil {
+		return err
+	}
+	if len(peeked.messages) == 0 {
+		utils.Printlnf("no messages in queue %s", o.src)
+		return nil
+	}
+	var matched []*kubemq2.QueueMessage
+	for _, msg := range peeked.messages {
+		if !o.filter.match(msg) {
+			continue
+		}
+		utils.Printlnf("[%s] -> [%s] {id: %s, metadata: %s}", o.src, o.dst, msg.MessageID, msg.Metadata)
+		matched = append(matched, newQueueArchiveRecord(msg).toQueueMessage(dstClient, o.dst))
+	}
+	scanned := len(peeked.messages)
+	if o.dryRun {
+		utils.Printlnf("dry-run: scanned %d messages, %d would be transferred", scanned, len(matched))
+		if peeked.truncated {
+			utils.Printlnf("%s, use 'queue move' to transfer deeper messages", peeked.note(o.src))
+		}
+		return nil
+	}
+	batchSize := o.batchSize
+	if batchSize > limits.maxReceive {
+		batchSize = limits.maxReceive
+	}
+	copied, failed := 0, 0
+	for start := 0; start < len(matched); start += batchSize {
+		end := start + batchSize
+		if end > len(matched) {
+			end = len(matched)
+		}
+		results, err := dstClient.SendQueueMessages(ctx, matched[start:end])
+		if err != nil {
+			utils.Printlnf("scanned %d messages, copied %d, %d failed", scanned, copied, failed)
+			return fmt.Errorf("send queue messages, %s", err.Error())
+		}
+		for _, r := range results {
+			if r.IsError {
+				failed++
+				utils.Printlnf("message %s failed, %s", r.MessageID, r.Error)
+			} else {
+				copied++
+			}
+		}
+	}
+	utils.Printlnf("scanned %d messages, copied %d, %d failed", scanned, copied, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d messages failed to copy", failed)
+	}
+	if peeked.truncated {
+		return fmt.Errorf("%s, use 'queue move' to transfer deeper messages", peeked.note(o.src))
+	}
+	return nil
+}
+
+func (o *QueueTransferOptions) runMove(ctx context.Context, client, dstClient *kubemq2.Client) error {
+	scanner := &queueScanner{
+		client:     client,
+		channel:    o.src,
+		visibility: o.visibility,
+		wait:       o.wait,
+		maxHeld:    o.maxHeld,
+		max:        o.max,
+	}
+	crossCluster := dstClient != client
+	stats, err := scanner.scan(ctx, func(msg *kubemq2.QueueMessage) (scanAction, *kubemq2.QueueMessage, error) {
+		if !o.filter.match(msg) {
+			return scanSkip, nil, nil
+		}
+		utils.Printlnf("[%s] -> [%s] {id: %s, metadata: %s}", o.src, o.dst, msg.MessageID, msg.Metadata)
+		if !crossCluster {
+			return scanResend, newQueueArchiveRecord(msg).toQueueMessage(client, o.dst), nil
+		}
+		// other cluster cannot join the stream transaction, ack the source only after the destination accepted the message
+		res, err := newQueueArchiveRecord(msg).toQueueMessage(dstClient, o.dst).Send(ctx)
+		if err != nil {
+			return scanSkip, nil, fmt.Errorf("send message %s to destination, %s", msg.MessageID, err.Error())
+		}
+		if res.IsError {
+			return scanSkip, nil, fmt.Errorf("send message %s to destination, %s", msg.MessageID, res.Error)
+		}
+		return scanAck, nil, nil
+	})
+	if stats != nil {
+		utils.Printlnf("%s, moved %d, left %d in queue %s", stats.summary(err), stats.Acked+stats.Resent, stats.Skipped, o.src)
+	}
+	return err
+}
+
+func queueDepth(ctx context.Context, client *kubemq2.Client, channel string) (int, error) {
+	info, err := client.QueuesInfo(ctx, channel)
+	if err != nil {
+		return 0, err
+	}
+	for _, q := range info.Queues {
+		if q.Name == channel {
+			return int(q.Messages), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,267 @@
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const tarEntryName = "messages.ndjson"
+
+type Format int
+
+const (
+	FormatNDJSON Format = iota
+	FormatGzip
+	FormatTarGzip
+)
+
+// FormatFromFileName returns the archive format according to the file name extension
+func FormatFromFileName(fileName string) Format {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGzip
+	case strings.HasSuffix(name, ".gz"):
+		return FormatGzip
+	default:
+		return FormatNDJSON
+	}
+}
+
+// Writer writes records as newline delimited json into a plain, gzip or tar.gz file. A file name of "-" writes to stdout
+type Writer struct {
+	format Format
+	file   *os.File
+	tmp    *os.File
+	gz     *gzip.Writer
+	buf    *bufio.Writer
+	count  int
+	closed bool
+}
+
+func NewWriter(fileName string) (*Writer, error) {
+	w := &Writer{
+		format: FormatFromFileName(fileName),
+	}
+	if fileName == "-" {
+		w.buf = bufio.NewWriter(os.Stdout)
+		return w, nil
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("create archive file, %s", err.Error())
+	}
+	w.file = file
+	switch w.format {
+	case FormatGzip:
+		w.gz = gzip.NewWriter(file)
+		w.buf = bufio.NewWriter(w.gz)
+	case FormatTarGzip:
+		// tar entries must know their size upfront, records are staged in a temp file until close
+		tmp, err := ioutil.TempFile("", "kubemqctl-archive-")
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("create archive temp file, %s", err.Error())
+		}
+		w.tmp = tmp
+		w.buf = bufio.NewWriter(tmp)
+	default:
+		w.buf = bufio.NewWriter(file)
+	}
+	return w, nil
+}
+
+// Write marshals the record to json and appends it as a single line
+func (w *Writer) Write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal archive record, %s", err.Error())
+	}
+	if _, err := w.buf.Write(data); err != nil {
+		return err
+	}
+	if err := w.buf.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Flush pushes buffered records to the underlying file, gzip compressed output is flushed as well
+func (w *Writer) Flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	if w.gz != nil {
+		return w.gz.Flush()
+	}
+	return nil
+}
+
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Close flushes and finalizes the archive, calling Close more than once is a no-op
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	switch w.format {
+	case FormatGzip:
+		if w.gz != nil {
+			if err := w.gz.Close(); err != nil {
+				return err
+			}
+		}
+	case FormatTarGzip:
+		if w.tmp != nil {
+			if err := w.writeTar(); err != nil {
+				return err
+			}
+		}
+	}
+	if w.file != nil {
+		return w.file.Close()
+	}
+	return nil
+}
+
+func (w *Writer) writeTar() error {
+	defer func() {
+		_ = w.tmp.Close()
+		_ = os.Remove(w.tmp.Name())
+	}()
+	info, err := w.tmp.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w.file)
+	tw := tar.NewWriter(gz)
+	hdr := &tar.Header{
+		Name:    tarEntryName,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, w.tmp); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Reader reads newline delimited json records from a plain, gzip or tar.gz file. A file name of "-" reads from stdin
+type Reader struct {
+	file *os.File
+	gz   *gzip.Reader
+	r    *bufio.Reader
+	line int
+}
+
+func NewReader(fileName string) (*Reader, error) {
+	r := &Reader{}
+	if fileName == "-" {
+		r.r = bufio.NewReader(os.Stdin)
+		return r, nil
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("open archive file, %s", err.Error())
+	}
+	r.file = file
+	switch FormatFromFileName(fileName) {
+	case FormatGzip:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("open gzip archive, %s", err.Error())
+		}
+		r.gz = gz
+		r.r = bufio.NewReader(gz)
+	case FormatTarGzip:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("open tar.gz archive, %s", err.Error())
+		}
+		r.gz = gz
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				_ = r.Close()
+				return nil, fmt.Errorf("no records entry found in archive %s", filepath.Base(fileName))
+			}
+			if err != nil {
+				_ = r.Close()
+				return nil, fmt.Errorf("read tar.gz archive, %s", err.Error())
+			}
+			if hdr.Typeflag == tar.TypeReg {
+				break
+			}
+		}
+		r.r = bufio.NewReader(tr)
+	default:
+		r.r = bufio.NewReader(file)
+	}
+	return r, nil
+}
+
+// Next unmarshals the next record into v, returns io.EOF when no more records are available. Empty lines are skipped
+func (r *Reader) Next(v interface{}) error {
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return err
+		}
+		r.line++
+		data = []byte(strings.TrimSpace(string(data)))
+		if len(data) == 0 {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if jsonErr := json.Unmarshal(data, v); jsonErr != nil {
+			return fmt.Errorf("invalid archive record at line %d, %s", r.line, jsonErr.Error())
+		}
+		return nil
+	}
+}
+
+// Line returns the line number of the last record read
+func (r *Reader) Line() int {
+	return r.line
+}
+
+func (r *Reader) Close() error {
+	if r.gz != nil {
+		_ = r.gz.Close()
+	}
+	if r.file != nil {
+		return r.file.Close()
+	}
+	return nil
+}
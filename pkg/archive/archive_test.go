@@ -0,0 +1,69 @@
+package archive
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	Id   int    `json:"id"`
+	Body []byte `json:"body"`
+}
+
+func TestArchive_WriteRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		format   Format
+	}{
+		{
+			name:     "ndjson",
+			fileName: "records.ndjson",
+			format:   FormatNDJSON,
+		},
+		{
+			name:     "gzip",
+			fileName: "records.ndjson.gz",
+			format:   FormatGzip,
+		},
+		{
+			name:     "tar.gz",
+			fileName: "records.tar.gz",
+			format:   FormatTarGzip,
+		},
+		{
+			name:     "tgz",
+			fileName: "records.TGZ",
+			format:   FormatTarGzip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.format, FormatFromFileName(tt.fileName))
+			fileName := filepath.Join(t.TempDir(), tt.fileName)
+			w, err := NewWriter(fileName)
+			require.NoError(t, err)
+			for i := 0; i < 10; i++ {
+				require.NoError(t, w.Write(&testRecord{Id: i, Body: []byte{0, byte(i), '\n', 0xff}}))
+			}
+			require.Equal(t, 10, w.Count())
+			require.NoError(t, w.Close())
+			require.NoError(t, w.Close())
+
+			r, err := NewReader(fileName)
+			require.NoError(t, err)
+			defer r.Close()
+			for i := 0; i < 10; i++ {
+				rec := &testRecord{}
+				require.NoError(t, r.Next(rec))
+				require.Equal(t, i, rec.Id)
+				require.Equal(t, []byte{0, byte(i), '\n', 0xff}, rec.Body)
+				require.Equal(t, i+1, r.Line())
+			}
+			require.Equal(t, io.EOF, r.Next(&testRecord{}))
+		})
+	}
+}
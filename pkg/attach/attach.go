@@ -3,9 +3,11 @@ package attach
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/kubemq-io/kubemqctl/pkg/schema"
 	"github.com/kubemq-io/kubemqctl/pkg/utils"
 	"regexp"
 	"strings"
@@ -17,7 +19,9 @@ import (
 
 var retries = 10
 
-func runWebsocketClientReaderWriter(ctx context.Context, uri string, chRead chan string, chWrite chan string, ready chan struct{}, errCh chan error) {
+// runWebsocketClientReaderWriter reads the attached messages until ctx is done, a read error exits the process when
+// exitOnError is set and is sent to errCh otherwise
+func runWebsocketClientReaderWriter(ctx context.Context, uri string, chRead chan string, chWrite chan string, ready chan struct{}, errCh chan error, exitOnError bool) {
 	var c *websocket.Conn
 	for i := 0; i < retries; i++ {
 		conn, res, err := websocket.DefaultDialer.Dial(uri, nil)
@@ -66,12 +70,22 @@ func runWebsocketClientReaderWriter(ctx context.Context, uri string, chRead chan
 	for {
 		_, message, err := c.ReadMessage()
 		if err != nil {
+			// the connection is closed when ctx is done
+			if ctx.Err() != nil {
+				return
+			}
 			utils.Printlnf("error: attach web socket reading, %s", err.Error())
-			os.Exit(0)
+			if exitOnError {
+				os.Exit(0)
+			}
 			errCh <- err
 			return
 		} else {
-			chRead <- string(message)
+			select {
+			case chRead <- string(message):
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 
@@ -82,18 +96,67 @@ type Message struct {
 	Message  string
 }
 
-func Run(ctx context.Context, cfg *config.Config, resources []string, include []string, exclude []string) error {
+// Run attaches to the resources channels, messages bodies are validated when a schema is set for the channel
+func Run(ctx context.Context, cfg *config.Config, resources []string, include []string, exclude []string, schemas *schema.Options) error {
+	validators := map[string]*schema.Validator{}
 	for _, rsc := range resources {
 		pair := strings.Split(rsc, "/")
 		if len(pair) != 2 {
 			return fmt.Errorf("invalid resource, %s", rsc)
 		}
-		go runner(ctx, cfg, pair[0], pair[1], include, exclude)
+		v, err := schemas.Validator(cfg, pair[1])
+		if err != nil {
+			return err
+		}
+		validators[rsc] = v
+	}
+	for _, rsc := range resources {
+		pair := strings.Split(rsc, "/")
+		go runner(ctx, cfg, pair[0], pair[1], include, exclude, validators[rsc])
 	}
 	return nil
 }
 
-func runner(ctx context.Context, cfg *config.Config, resType, resChannel string, include []string, exclude []string) {
+// connect starts attaching to a resource channel and returns the raw attached messages channel
+func connect(ctx context.Context, cfg *config.Config, resType, resChannel string, exitOnError bool) (chan string, chan error) {
+	uri := fmt.Sprintf("%s/v1/stats/attach?channel=%s&kind=%s", cfg.GetApiWsURI(), resChannel, resType)
+
+	rxChan := make(chan string, 10)
+	txChan := make(chan string, 10)
+	ready := make(chan struct{})
+	errCh := make(chan error, 10)
+	go runWebsocketClientReaderWriter(ctx, uri, rxChan, txChan, ready, errCh, exitOnError)
+	<-ready
+	txChan <- "start"
+	return rxChan, errCh
+}
+
+// Subscribe attaches to a resource channel, i.e. queries/some-channel, and returns the attached messages, base64
+// messages are decoded. The messages channel is closed when ctx is done or the connection fails
+func Subscribe(ctx context.Context, cfg *config.Config, resType, resChannel string) <-chan string {
+	rxChan, errCh := connect(ctx, cfg, resType, resChannel, false)
+	msgCh := make(chan string, 100)
+	go func() {
+		defer close(msgCh)
+		for {
+			select {
+			case msg := <-rxChan:
+				select {
+				case msgCh <- decodeBase64(msg):
+				case <-ctx.Done():
+					return
+				}
+			case <-errCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return msgCh
+}
+
+func runner(ctx context.Context, cfg *config.Config, resType, resChannel string, include []string, exclude []string, v *schema.Validator) {
 	var exc []*regexp.Regexp
 	var inc []*regexp.Regexp
 	for _, ex := range exclude {
@@ -111,15 +174,7 @@ func runner(ctx context.Context, cfg *config.Config, resType, resChannel string,
 		inc = append(inc, rin)
 	}
 
-	uri := fmt.Sprintf("%s/v1/stats/attach?channel=%s&kind=%s", cfg.GetApiWsURI(), resChannel, resType)
-
-	rxChan := make(chan string, 10)
-	txChan := make(chan string, 10)
-	ready := make(chan struct{})
-	errCh := make(chan error, 10)
-	go runWebsocketClientReaderWriter(ctx, uri, rxChan, txChan, ready, errCh)
-	<-ready
-	txChan <- "start"
+	rxChan, errCh := connect(ctx, cfg, resType, resChannel, true)
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
 
 OUTER:
@@ -144,9 +199,14 @@ OUTER:
 					continue OUTER
 				}
 			}
+			schemaErr := schema.Error(v, attachedBody(msg))
 			msg = strings.Replace(msg, "\n", "", -1)
 			msg = strings.Replace(msg, "\t", " ", -1)
-			fmt.Fprintf(w, "[%s]\t[%s]\t%s\n", resType, resChannel, decodeBase64(msg))
+			if schemaErr != "" {
+				fmt.Fprintf(w, "[%s]\t[%s]\t%s\t[schema error: %s]\n", resType, resChannel, decodeBase64(msg), schemaErr)
+			} else {
+				fmt.Fprintf(w, "[%s]\t[%s]\t%s\n", resType, resChannel, decodeBase64(msg))
+			}
 			w.Flush()
 		case <-ctx.Done():
 			return
@@ -159,6 +219,31 @@ OUTER:
 	}
 }
 
+// attachedBody returns the body of an attached message, the message is either a json object with the message body
+// field, or the body itself
+func attachedBody(msg string) []byte {
+	data := []byte(decodeBase64(msg))
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+	body, ok := fields["Body"]
+	if !ok {
+		if body, ok = fields["body"]; !ok {
+			return data
+		}
+	}
+	// []byte bodies are marshaled as base64 strings
+	var str string
+	if err := json.Unmarshal(body, &str); err == nil {
+		if dec, err := base64.StdEncoding.DecodeString(str); err == nil {
+			return dec
+		}
+		return []byte(str)
+	}
+	return body
+}
+
 func decodeBase64(in string) string {
 	// base64 string cannot contain space so this is indication of base64 string
 	if !strings.Contains(in, " ") {
@@ -64,6 +64,27 @@ type Config struct {
 	AuthTokenFile      string
 	LicenseData        string
 	LicenseKey         string
+	Schemas            []*ChannelSchema `yaml:",omitempty"`
+}
+
+// ChannelSchema sets the JSON Schema file validating the message bodies of a channel, channel can be a glob pattern
+type ChannelSchema struct {
+	Channel string
+	Schema  string
+}
+
+// LoadConfig loads a kubemqctl configuration file, i.e. for connecting to a second cluster
+func LoadConfig(fileName string) (*Config, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("load kubemqctl configuration file, %s", err.Error())
+	}
+	cfg := &Config{}
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubemqctl configuration file %s, %s", fileName, err.Error())
+	}
+	return cfg, nil
 }
 
 func (c *Config) Save() error {
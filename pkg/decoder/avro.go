@@ -0,0 +1,83 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+func init() {
+	Register("avro", newAvro)
+}
+
+var avroOCFMagic = []byte("Obj\x01")
+
+// avro decodes avro bodies into json. Object container files carry their own schema, single object encoded and raw
+// binary bodies are decoded with the schema file
+type avro struct {
+	codec *goavro.Codec
+}
+
+func newAvro(opts *Options) (Decoder, error) {
+	a := &avro{}
+	if opts.AvroSchema == "" {
+		return a, nil
+	}
+	schema, err := ioutil.ReadFile(opts.AvroSchema)
+	if err != nil {
+		return nil, fmt.Errorf("read avro schema file, %s", err.Error())
+	}
+	if a.codec, err = goavro.NewCodec(string(schema)); err != nil {
+		return nil, fmt.Errorf("invalid avro schema, %s", err.Error())
+	}
+	return a, nil
+}
+
+func (a *avro) Decode(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, avroOCFMagic) {
+		return a.decodeOCF(data)
+	}
+	if a.codec == nil {
+		return nil, fmt.Errorf("missing avro schema file, use --avro-schema")
+	}
+	var native interface{}
+	var err error
+	if len(data) > 2 && data[0] == 0xC3 && data[1] == 0x01 {
+		native, _, err = a.codec.NativeFromSingle(data)
+	} else {
+		native, _, err = a.codec.NativeFromBinary(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro body, %s", err.Error())
+	}
+	return a.codec.TextualFromNative(nil, native)
+}
+
+func (a *avro) decodeOCF(data []byte) ([]byte, error) {
+	r, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro object container body, %s", err.Error())
+	}
+	var records []json.RawMessage
+	for r.Scan() {
+		native, err := r.Read()
+		if err != nil {
+			return nil, fmt.Errorf("invalid avro object container body, %s", err.Error())
+		}
+		record, err := r.Codec().TextualFromNative(nil, native)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("invalid avro object container body, %s", err.Error())
+	}
+	if len(records) == 1 {
+		return records[0], nil
+	}
+	return json.Marshal(records)
+}
@@ -0,0 +1,80 @@
+package decoder
+
+import (
+	"bytes"
+	"compress/gzip"
+	b64 "encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	Register("auto", func(opts *Options) (Decoder, error) { return auto{}, nil })
+	Register("text", func(opts *Options) (Decoder, error) { return text{}, nil })
+	Register("base64", func(opts *Options) (Decoder, error) { return base64{}, nil })
+	Register("gzip", func(opts *Options) (Decoder, error) { return gunzip{}, nil })
+	Register("zstd", func(opts *Options) (Decoder, error) { return unzstd{}, nil })
+}
+
+// auto returns json bodies as is, other bodies are base64 decoded when possible or returned as text
+type auto struct{}
+
+func (auto) Decode(data []byte) ([]byte, error) {
+	if isJSON(data) {
+		return data, nil
+	}
+	if decoded, err := b64.StdEncoding.DecodeString(string(data)); err == nil {
+		return decoded, nil
+	}
+	return data, nil
+}
+
+type text struct{}
+
+func (text) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+type base64 struct{}
+
+func (base64) Decode(data []byte) ([]byte, error) {
+	decoded, err := b64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 body, %s", err.Error())
+	}
+	return decoded, nil
+}
+
+type gunzip struct{}
+
+func (gunzip) Decode(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip body, %s", err.Error())
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	decoded, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip body, %s", err.Error())
+	}
+	return decoded, nil
+}
+
+type unzstd struct{}
+
+func (unzstd) Decode(data []byte) ([]byte, error) {
+	r, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	decoded, err := r.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd body, %s", err.Error())
+	}
+	return decoded, nil
+}
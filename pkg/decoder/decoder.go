@@ -0,0 +1,122 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Decoder converts a message body into a readable form, decoders can be chained so the output of one is the input
+// of the next one
+type Decoder interface {
+	Decode(data []byte) ([]byte, error)
+}
+
+// Factory creates a decoder from the decoding options
+type Factory func(opts *Options) (Decoder, error)
+
+var registry = map[string]Factory{}
+
+// Register adds a named decoder to the registry, registering an existing name replaces it
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// Names returns the names of all registered decoders
+func Names() []string {
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options holds the decoder selection and the settings decoders need, i.e. schema files
+type Options struct {
+	Decode          string
+	ProtoDescriptor string
+	ProtoMessage    string
+	AvroSchema      string
+}
+
+// AddFlags adds the decoding flags to cmd
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.Decode, "decode", "", "auto", fmt.Sprintf("set body decoders chain separated by comma, i.e. gzip,protobuf (%s)", strings.Join(Names(), ", ")))
+	cmd.PersistentFlags().StringVarP(&o.ProtoDescriptor, "proto-descriptor", "", "", "set protobuf descriptor set file (protoc --include_imports --descriptor_set_out) for protobuf decoder")
+	cmd.PersistentFlags().StringVarP(&o.ProtoMessage, "proto-message", "", "", "set protobuf full message name for protobuf decoder, i.e. orders.v1.Order")
+	cmd.PersistentFlags().StringVarP(&o.AvroSchema, "avro-schema", "", "", "set avro schema file for avro decoder")
+}
+
+// Decoder returns the decoders chain set by the options
+func (o *Options) Decoder() (Decoder, error) {
+	spec := strings.TrimSpace(o.Decode)
+	if spec == "" {
+		spec = "auto"
+	}
+	var c chain
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown decoder %s, available decoders: %s", name, strings.Join(Names(), ", "))
+		}
+		d, err := factory(o)
+		if err != nil {
+			return nil, fmt.Errorf("create %s decoder, %s", name, err.Error())
+		}
+		c = append(c, d)
+	}
+	if len(c) == 1 {
+		return c[0], nil
+	}
+	return c, nil
+}
+
+type chain []Decoder
+
+func (c chain) Decode(data []byte) ([]byte, error) {
+	var err error
+	for _, d := range c {
+		if data, err = d.Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Body decodes data with d, a nil decoder uses the auto decoder. A json result is returned as json, any other result
+// as string. When decoding fails the raw body is returned as string with the decoding error
+func Body(d Decoder, data []byte) (json.RawMessage, string, error) {
+	if d == nil {
+		d = auto{}
+	}
+	decoded, err := d.Decode(data)
+	if err != nil {
+		return nil, string(data), err
+	}
+	if isJSON(decoded) {
+		return decoded, "", nil
+	}
+	return nil, string(decoded), nil
+}
+
+// String decodes data with d and returns the result as a single string
+func String(d Decoder, data []byte) string {
+	js, str, err := Body(d, data)
+	if err != nil {
+		return fmt.Sprintf("%s (decode error: %s)", str, err.Error())
+	}
+	if js != nil {
+		return string(js)
+	}
+	return str
+}
+
+func isJSON(data []byte) bool {
+	var js json.RawMessage
+	return json.Unmarshal(data, &js) == nil
+}
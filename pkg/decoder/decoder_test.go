@@ -0,0 +1,125 @@
+package decoder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/kubemq-io/kubemqctl/pkg/targets"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func gzipData(t *testing.T, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdData(t *testing.T, data []byte) []byte {
+	w, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = w.Close()
+	}()
+	return w.EncodeAll(data, nil)
+}
+
+func msgpackData(t *testing.T, v interface{}) []byte {
+	data, err := msgpack.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+const avroTestSchema = `{"type":"record","name":"order","fields":[{"name":"id","type":"string"},{"name":"amount","type":"int"}]}`
+
+func avroSchemaFile(t *testing.T) string {
+	fileName := filepath.Join(t.TempDir(), "order.avsc")
+	require.NoError(t, ioutil.WriteFile(fileName, []byte(avroTestSchema), 0644))
+	return fileName
+}
+
+func avroData(t *testing.T) []byte {
+	codec, err := goavro.NewCodec(avroTestSchema)
+	require.NoError(t, err)
+	data, err := codec.BinaryFromNative(nil, map[string]interface{}{"id": "o-1", "amount": 10})
+	require.NoError(t, err)
+	return data
+}
+
+func protoDescriptorFile(t *testing.T) string {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(durationpb.File_google_protobuf_duration_proto)},
+	}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+	fileName := filepath.Join(t.TempDir(), "set.pb")
+	require.NoError(t, ioutil.WriteFile(fileName, data, 0644))
+	return fileName
+}
+
+func protoData(t *testing.T) []byte {
+	data, err := proto.Marshal(durationpb.New(1500000000))
+	require.NoError(t, err)
+	return data
+}
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *Options
+		data       []byte
+		wantJson   string
+		wantString string
+		wantErr    bool
+	}{
+		{name: "auto json", opts: &Options{}, data: []byte(`{"a":1}`), wantJson: `{"a":1}`},
+		{name: "auto base64", opts: &Options{Decode: "auto"}, data: []byte("aGVsbG8="), wantString: "hello"},
+		{name: "auto text", opts: &Options{Decode: "auto"}, data: []byte("hello world"), wantString: "hello world"},
+		{name: "text", opts: &Options{Decode: "text"}, data: []byte("aGVsbG8="), wantString: "aGVsbG8="},
+		{name: "gzip", opts: &Options{Decode: "gzip"}, data: gzipData(t, []byte(`{"a":1}`)), wantJson: `{"a":1}`},
+		{name: "zstd", opts: &Options{Decode: "zstd"}, data: zstdData(t, []byte("hello")), wantString: "hello"},
+		{name: "msgpack", opts: &Options{Decode: "msgpack"}, data: msgpackData(t, map[string]interface{}{"a": 1}), wantJson: `{"a":1}`},
+		{name: "chain", opts: &Options{Decode: "gzip,msgpack"}, data: gzipData(t, msgpackData(t, []string{"x"})), wantJson: `["x"]`},
+		{name: "targets", opts: &Options{Decode: "targets"}, data: targets.NewRequest().SetMetadataKeyValue("method", "get").SetData([]byte("hello")).MarshalBinary(), wantJson: `{"metadata":{"method":"get"},"data_string":"hello"}`},
+		{name: "protobuf", opts: &Options{Decode: "protobuf", ProtoDescriptor: protoDescriptorFile(t)}, data: protoData(t), wantJson: `"1.500s"`},
+		{name: "avro", opts: &Options{Decode: "avro", AvroSchema: avroSchemaFile(t)}, data: avroData(t), wantJson: `{"id":"o-1","amount":10}`},
+		{name: "invalid gzip", opts: &Options{Decode: "gzip"}, data: []byte("hello"), wantString: "hello", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.opts.Decoder()
+			require.NoError(t, err)
+			js, str, err := Body(d, tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantJson != "" {
+				require.JSONEq(t, tt.wantJson, string(js))
+			}
+			require.Equal(t, tt.wantString, str)
+		})
+	}
+}
+
+func TestOptions_Decoder(t *testing.T) {
+	_, err := (&Options{Decode: "unknown"}).Decoder()
+	require.Error(t, err)
+	_, err = (&Options{Decode: "protobuf"}).Decoder()
+	require.Error(t, err)
+	_, err = (&Options{Decode: "protobuf", ProtoDescriptor: protoDescriptorFile(t), ProtoMessage: "google.protobuf.Missing"}).Decoder()
+	require.Error(t, err)
+}
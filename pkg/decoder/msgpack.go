@@ -0,0 +1,46 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	Register("msgpack", func(opts *Options) (Decoder, error) { return msgPack{}, nil })
+}
+
+type msgPack struct{}
+
+func (msgPack) Decode(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid msgpack body, %s", err.Error())
+	}
+	return json.Marshal(jsonCompatible(value))
+}
+
+// jsonCompatible converts maps with non string keys, which json cannot encode, into maps with string keys
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonCompatible(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,72 @@
+package decoder
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func init() {
+	Register("protobuf", newProtobuf)
+}
+
+// protobuf decodes binary protobuf bodies into json using a message type from a descriptor set file
+type protobuf struct {
+	desc protoreflect.MessageDescriptor
+}
+
+func newProtobuf(opts *Options) (Decoder, error) {
+	if opts.ProtoDescriptor == "" {
+		return nil, fmt.Errorf("missing protobuf descriptor set file, use --proto-descriptor")
+	}
+	data, err := ioutil.ReadFile(opts.ProtoDescriptor)
+	if err != nil {
+		return nil, fmt.Errorf("read protobuf descriptor set file, %s", err.Error())
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set file, %s", err.Error())
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set file, %s", err.Error())
+	}
+	name := opts.ProtoMessage
+	if name == "" {
+		// a descriptor set with a single message type does not need the message name
+		var names []protoreflect.FullName
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			for i := 0; i < fd.Messages().Len(); i++ {
+				names = append(names, fd.Messages().Get(i).FullName())
+			}
+			return true
+		})
+		if len(names) != 1 {
+			return nil, fmt.Errorf("descriptor set has %d message types, use --proto-message to set the message type", len(names))
+		}
+		name = string(names[0])
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf message %s was not found in descriptor set", name)
+	}
+	desc, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a protobuf message type", name)
+	}
+	return &protobuf{desc: desc}, nil
+}
+
+func (p *protobuf) Decode(data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(p.desc)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid %s protobuf body, %s", p.desc.FullName(), err.Error())
+	}
+	return protojson.Marshal(msg)
+}
@@ -0,0 +1,33 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubemq-io/kubemqctl/pkg/targets"
+)
+
+func init() {
+	Register("targets", func(opts *Options) (Decoder, error) { return targetsRequest{}, nil })
+}
+
+// targetsRequest decodes a kubemq targets request envelope, the request data is decoded with the auto decoder
+type targetsRequest struct{}
+
+type targetsRequestObject struct {
+	Metadata   targets.Metadata `json:"metadata,omitempty"`
+	DataJson   json.RawMessage  `json:"data_json,omitempty"`
+	DataString string           `json:"data_string,omitempty"`
+}
+
+func (targetsRequest) Decode(data []byte) ([]byte, error) {
+	req, err := targets.ParseRequest(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid targets request body, %s", err.Error())
+	}
+	obj := &targetsRequestObject{
+		Metadata: req.Metadata,
+	}
+	obj.DataJson, obj.DataString, _ = Body(nil, req.Data)
+	return json.Marshal(obj)
+}
@@ -0,0 +1,170 @@
+// Package generator renders message bodies and metadata from text/template templates with test data functions
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Template is a message field template rendered per message, text without actions is rendered as is
+type Template struct {
+	mu    sync.Mutex
+	text  string
+	tmpl  *template.Template
+	seq   int
+	rnd   *rand.Rand
+	files map[string]string
+}
+
+// New parses a template, the template functions are:
+//
+//	uuid                     a random uuid
+//	now [layout]             the current time, RFC3339Nano unless a layout is set, i.e. {{now "2006-01-02"}}
+//	seq                      the message sequence number in the send run, starting at 1
+//	randInt min max          a random integer between min and max inclusive
+//	randChoice item...       a random item of the items, i.e. {{randChoice "eu" "us"}}
+//	env name                 the value of an environment variable
+//	file path                the content of a file
+func New(name, text string) (*Template, error) {
+	t := &Template{
+		text:  text,
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		files: map[string]string{},
+	}
+	if !strings.Contains(text, "{{") {
+		return t, nil
+	}
+	tmpl, err := template.New(name).Funcs(t.funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s template, %s", name, err.Error())
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Literal returns a template which renders text as is, even when text contains actions
+func Literal(text string) *Template {
+	return &Template{
+		text: text,
+	}
+}
+
+// IsStatic returns true when the template has no actions and renders the same text for every message
+func (t *Template) IsStatic() bool {
+	return t.tmpl == nil
+}
+
+// Render renders the template of message seq
+func (t *Template) Render(seq int) (string, error) {
+	return t.Execute(seq, nil)
+}
+
+// Execute renders the template of message seq with data as the template dot, i.e. {{.Body}}
+func (t *Template) Execute(seq int, data interface{}) (string, error) {
+	if t.tmpl == nil {
+		return t.text, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq = seq
+	sb := &strings.Builder{}
+	if err := t.tmpl.Execute(sb, data); err != nil {
+		return "", fmt.Errorf("render %s template, %s", t.tmpl.Name(), err.Error())
+	}
+	return sb.String(), nil
+}
+
+// funcs are called by Execute while the template lock is held
+func (t *Template) funcs() template.FuncMap {
+	return template.FuncMap{
+		"uuid": func() string {
+			return uuid.New().String()
+		},
+		"now": func(layout ...string) string {
+			if len(layout) > 0 {
+				return time.Now().Format(layout[0])
+			}
+			return time.Now().Format(time.RFC3339Nano)
+		},
+		"seq": func() int {
+			return t.seq
+		},
+		"randInt": func(min, max int) (int, error) {
+			if max < min {
+				return 0, fmt.Errorf("randInt max %d is less than min %d", max, min)
+			}
+			return min + t.rnd.Intn(max-min+1), nil
+		},
+		"randChoice": func(items ...interface{}) (interface{}, error) {
+			if len(items) == 0 {
+				return nil, fmt.Errorf("randChoice requires at least one item")
+			}
+			return items[t.rnd.Intn(len(items))], nil
+		},
+		"env": os.Getenv,
+		"file": func(path string) (string, error) {
+			if data, ok := t.files[path]; ok {
+				return data, nil
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			t.files[path] = string(data)
+			return string(data), nil
+		},
+	}
+}
+
+// Message is the body and metadata templates of the messages of a send run
+type Message struct {
+	body     *Template
+	metadata *Template
+}
+
+// NewMessage parses the body and metadata templates, a field is sent as is when it is not templated, i.e. file and
+// built bodies which may contain braces of their own
+func NewMessage(body, metadata string, templateBody, templateMetadata bool) (*Message, error) {
+	b, m := Literal(body), Literal(metadata)
+	var err error
+	if templateBody {
+		if b, err = New("body", body); err != nil {
+			return nil, err
+		}
+	}
+	if templateMetadata {
+		if m, err = New("metadata", metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &Message{
+		body:     b,
+		metadata: m,
+	}, nil
+}
+
+// IsStatic returns true when every message of the run has the same body and metadata
+func (m *Message) IsStatic() bool {
+	return m.body.IsStatic() && m.metadata.IsStatic()
+}
+
+// Render returns the body and metadata of message seq, seq starts at 1
+func (m *Message) Render(seq int) ([]byte, string, error) {
+	body, err := m.body.Render(seq)
+	if err != nil {
+		return nil, "", err
+	}
+	metadata, err := m.metadata.Render(seq)
+	if err != nil {
+		return nil, "", err
+	}
+	return []byte(body), metadata, nil
+}
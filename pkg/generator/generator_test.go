@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Render(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "name.txt")
+	require.NoError(t, ioutil.WriteFile(file, []byte("kubemq"), 0644))
+	require.NoError(t, os.Setenv("GENERATOR_TEST_ENV", "staging"))
+	defer os.Unsetenv("GENERATOR_TEST_ENV")
+
+	tmpl, err := New("body", `{"id":"{{uuid}}","n":{{seq}},"v":{{randInt 5 7}},"region":"{{randChoice "eu" "us"}}","env":"{{env "GENERATOR_TEST_ENV"}}","name":"{{file "`+file+`"}}","day":"{{now "2006"}}"}`)
+	require.NoError(t, err)
+	require.False(t, tmpl.IsStatic())
+	ids := map[string]bool{}
+	for seq := 1; seq <= 20; seq++ {
+		out, err := tmpl.Render(seq)
+		require.NoError(t, err)
+		doc := struct {
+			Id     string `json:"id"`
+			N      int    `json:"n"`
+			V      int    `json:"v"`
+			Region string `json:"region"`
+			Env    string `json:"env"`
+			Name   string `json:"name"`
+			Day    string `json:"day"`
+		}{}
+		require.NoError(t, json.Unmarshal([]byte(out), &doc))
+		require.Equal(t, seq, doc.N)
+		require.GreaterOrEqual(t, doc.V, 5)
+		require.LessOrEqual(t, doc.V, 7)
+		require.Contains(t, []string{"eu", "us"}, doc.Region)
+		require.Equal(t, "staging", doc.Env)
+		require.Equal(t, "kubemq", doc.Name)
+		require.Len(t, doc.Day, 4)
+		require.False(t, ids[doc.Id])
+		ids[doc.Id] = true
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	_, err := New("body", `{{seq`)
+	require.Error(t, err)
+	tmpl, err := New("body", `{{randInt 5 1}}`)
+	require.NoError(t, err)
+	_, err = tmpl.Render(1)
+	require.Error(t, err)
+	tmpl, err = New("body", `{{file "/not/exists"}}`)
+	require.NoError(t, err)
+	_, err = tmpl.Render(1)
+	require.Error(t, err)
+}
+
+func TestMessage_Render(t *testing.T) {
+	m, err := NewMessage("static body {not a template}", "key-{{seq}}", true, true)
+	require.NoError(t, err)
+	require.False(t, m.IsStatic())
+	body, metadata, err := m.Render(3)
+	require.NoError(t, err)
+	require.Equal(t, "static body {not a template}", string(body))
+	require.Equal(t, "key-3", metadata)
+	m, err = NewMessage("body", "metadata", true, true)
+	require.NoError(t, err)
+	require.True(t, m.IsStatic())
+}
+
+func TestMessage_RenderLiteralBody(t *testing.T) {
+	// file and built bodies are sent unchanged, even when they are not valid templates
+	for _, text := range []string{`{"html":"<p>{{ name }}</p>"}`, `{"tmpl":"{{seq}}"}`} {
+		m, err := NewMessage(text, "", false, true)
+		require.NoError(t, err)
+		require.True(t, m.IsStatic())
+		body, _, err := m.Render(2)
+		require.NoError(t, err)
+		require.Equal(t, text, string(body))
+	}
+	_, err := NewMessage(`{{ name }}`, "", true, true)
+	require.Error(t, err)
+
+	// no template sends both fields as is
+	m, err := NewMessage(`{{ name }}`, "key-{{seq}}", false, false)
+	require.NoError(t, err)
+	require.True(t, m.IsStatic())
+	body, metadata, err := m.Render(1)
+	require.NoError(t, err)
+	require.Equal(t, `{{ name }}`, string(body))
+	require.Equal(t, "key-{{seq}}", metadata)
+}
@@ -0,0 +1,137 @@
+// Package grouptest tracks how the test messages of a consumer group run are distributed across the group members
+package grouptest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+)
+
+// Tracker records the group member that received each test message of a run, messages of other clients on the same
+// channel are ignored by their id prefix
+type Tracker struct {
+	mu       sync.Mutex
+	members  int
+	prefix   string
+	sent     []string
+	received map[string][]int
+}
+
+func NewTracker(members int) *Tracker {
+	return &Tracker{
+		members:  members,
+		prefix:   fmt.Sprintf("group-test-%s", uuid.New().String()),
+		received: map[string][]int{},
+	}
+}
+
+// NextId returns the id of the next test message and records it as sent
+func (t *Tracker) NextId() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id := fmt.Sprintf("%s-%d", t.prefix, len(t.sent)+1)
+	t.sent = append(t.sent, id)
+	return id
+}
+
+// Received records a message received by a member, false is returned for messages which are not part of the run
+func (t *Tracker) Received(member int, id string) bool {
+	if !strings.HasPrefix(id, t.prefix) {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.received[id] = append(t.received[id], member)
+	return true
+}
+
+// Done returns true when every sent message was received at least once
+func (t *Tracker) Done() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range t.sent {
+		if len(t.received[id]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Report summarizes the run
+func (t *Tracker) Report() *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := &Report{
+		Members:   t.members,
+		Sent:      len(t.sent),
+		PerMember: make([]int, t.members),
+	}
+	for _, id := range t.sent {
+		members := t.received[id]
+		switch {
+		case len(members) == 0:
+			r.Lost++
+		case len(members) > 1:
+			r.Duplicated++
+		}
+		if len(members) > 0 {
+			r.Received++
+		}
+		for _, m := range members {
+			r.Deliveries++
+			if m >= 0 && m < t.members {
+				r.PerMember[m]++
+			}
+		}
+	}
+	return r
+}
+
+// Report is the distribution of a run, Received counts unique messages and Deliveries counts every delivery
+type Report struct {
+	Members    int
+	Sent       int
+	Received   int
+	Deliveries int
+	Duplicated int
+	Lost       int
+	PerMember  []int
+}
+
+// Ok returns true when every message was delivered to exactly one member
+func (r *Report) Ok() bool {
+	return r.Duplicated == 0 && r.Lost == 0
+}
+
+// Idle returns the number of members which received no messages
+func (r *Report) Idle() int {
+	idle := 0
+	for _, cnt := range r.PerMember {
+		if cnt == 0 {
+			idle++
+		}
+	}
+	return idle
+}
+
+func (r *Report) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(tw, "MEMBER\tMESSAGES\tSHARE")
+	for i, cnt := range r.PerMember {
+		share := 0.0
+		if r.Deliveries > 0 {
+			share = float64(cnt) * 100 / float64(r.Deliveries)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%.1f%%\n", i+1, cnt, share)
+	}
+	fmt.Fprintf(tw, "\nSENT:\t%d\n", r.Sent)
+	fmt.Fprintf(tw, "RECEIVED:\t%d\n", r.Received)
+	fmt.Fprintf(tw, "DUPLICATED:\t%d\n", r.Duplicated)
+	fmt.Fprintf(tw, "LOST:\t%d\n", r.Lost)
+	fmt.Fprintf(tw, "IDLE MEMBERS:\t%d\n", r.Idle())
+	tw.Flush()
+}
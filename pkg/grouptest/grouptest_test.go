@@ -0,0 +1,36 @@
+package grouptest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Report(t *testing.T) {
+	tr := NewTracker(3)
+	var ids []string
+	for i := 0; i < 6; i++ {
+		ids = append(ids, tr.NextId())
+	}
+	require.False(t, tr.Received(0, "other-client-message"))
+	for i, id := range ids[:5] {
+		require.True(t, tr.Received(i%2, id))
+	}
+	require.False(t, tr.Done())
+	require.True(t, tr.Received(1, ids[0]))
+	r := tr.Report()
+	require.Equal(t, &Report{
+		Members:    3,
+		Sent:       6,
+		Received:   5,
+		Deliveries: 6,
+		Duplicated: 1,
+		Lost:       1,
+		PerMember:  []int{3, 3, 0},
+	}, r)
+	require.False(t, r.Ok())
+	require.Equal(t, 1, r.Idle())
+
+	require.True(t, tr.Received(2, ids[5]))
+	require.True(t, tr.Done())
+}
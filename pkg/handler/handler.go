@@ -0,0 +1,241 @@
+// Package handler forwards received 'commands' and 'queries' to an external process or HTTP endpoint and converts
+// their results to responses
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Request is the json document a handler receives for each command or query, a body which is not valid UTF-8 is set
+// base64 encoded in BodyBase64 instead of Body
+type Request struct {
+	Id         string            `json:"id"`
+	Type       string            `json:"type"`
+	Channel    string            `json:"channel"`
+	ClientId   string            `json:"client_id,omitempty"`
+	Metadata   string            `json:"metadata,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"body_base64,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// SetBody sets the request body, binary bodies are base64 encoded so json encoding does not replace invalid bytes
+func (r *Request) SetBody(body []byte) *Request {
+	if utf8.Valid(body) {
+		r.Body = string(body)
+	} else {
+		r.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	return r
+}
+
+// Response is the json document a handler returns, a non empty Error is sent as a failed (not executed) response.
+// A binary response body is returned base64 encoded in BodyBase64 instead of Body
+type Response struct {
+	Metadata   string            `json:"metadata,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"body_base64,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Data returns the response body, decoded when the body is base64 encoded
+func (r *Response) Data() ([]byte, error) {
+	if r.BodyBase64 == "" {
+		return []byte(r.Body), nil
+	}
+	if r.Body != "" {
+		return nil, fmt.Errorf("body and body_base64 cannot be set together")
+	}
+	data, err := base64.StdEncoding.DecodeString(r.BodyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body_base64, %s", err.Error())
+	}
+	return data, nil
+}
+
+// Apply sets the response fields on a kubemq response, responses with an error are sent as not executed. An invalid
+// base64 body is sent as an error response
+func (r *Response) Apply(resp *kubemq2.Response) *kubemq2.Response {
+	data, err := r.Data()
+	if err != nil {
+		return resp.SetMetadata(r.Metadata).SetError(fmt.Errorf("invalid handler response, %s", err.Error()))
+	}
+	resp.SetMetadata(r.Metadata).SetBody(data)
+	if len(r.Tags) > 0 {
+		resp.SetTags(r.Tags)
+	}
+	if r.Error != "" {
+		return resp.SetError(errors.New(r.Error))
+	}
+	return resp.SetExecutedAt(time.Now())
+}
+
+// Handler handles a request, an error is returned when the handler failed to produce a response
+type Handler interface {
+	Handle(ctx context.Context, req *Request) (*Response, error)
+	String() string
+}
+
+// execHandler runs a shell command per request, the request is written to the command stdin and the response is read
+// from its stdout
+type execHandler struct {
+	command string
+}
+
+func NewExec(command string) Handler {
+	return &execHandler{
+		command: command,
+	}
+}
+
+func (h *execHandler) Handle(ctx context.Context, req *Request) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	cmd := utils.ShellCommand(ctx, h.command)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// children of the shell may keep the output open after the shell is killed on timeout, so the run is not waited
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Run()
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("handler timed out")
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("handler timed out")
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("handler failed, %s: %s", err.Error(), msg)
+		}
+		return nil, fmt.Errorf("handler failed, %s", err.Error())
+	}
+	return parseResponse(stdout.Bytes())
+}
+
+func (h *execHandler) String() string {
+	return fmt.Sprintf("exec %s", h.command)
+}
+
+// httpHandler posts each request to an endpoint and reads the response from the body of a 2xx reply
+type httpHandler struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string) Handler {
+	return &httpHandler{
+		url:    url,
+		client: &http.Client{},
+	}
+}
+
+func (h *httpHandler) Handle(ctx context.Context, req *Request) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := h.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("handler timed out")
+		}
+		return nil, fmt.Errorf("handler request failed, %s", err.Error())
+	}
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read handler response, %s", err.Error())
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return nil, fmt.Errorf("handler returned %s: %s", httpResp.Status, msg)
+		}
+		return nil, fmt.Errorf("handler returned %s", httpResp.Status)
+	}
+	return parseResponse(body)
+}
+
+func (h *httpHandler) String() string {
+	return fmt.Sprintf("http %s", h.url)
+}
+
+// parseResponse parses a handler response document, an empty output is an executed response without a body
+func parseResponse(data []byte) (*Response, error) {
+	res := &Response{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return res, nil
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("invalid handler response, %s", err.Error())
+	}
+	if _, err := res.Data(); err != nil {
+		return nil, fmt.Errorf("invalid handler response, %s", err.Error())
+	}
+	return res, nil
+}
+
+// Options are the flags of commands which serve requests with a handler
+type Options struct {
+	Exec        string
+	HTTP        string
+	Concurrency int
+	Timeout     time.Duration
+}
+
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.Exec, "exec", "", "", "set shell command to run per request, the request json is written to its stdin and the response json is read from its stdout")
+	cmd.PersistentFlags().StringVarP(&o.HTTP, "http", "", "", "set http endpoint to post each request json to, the response json is read from the reply body")
+	cmd.PersistentFlags().IntVarP(&o.Concurrency, "concurrency", "c", 10, "set how many requests are handled at once")
+	cmd.PersistentFlags().DurationVarP(&o.Timeout, "timeout", "t", 30*time.Second, "set handler timeout of each request")
+}
+
+// Handler returns the handler set by the options, exactly one of exec and http must be set
+func (o *Options) Handler() (Handler, error) {
+	if o.Concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be a positive number")
+	}
+	if o.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be a positive duration")
+	}
+	switch {
+	case o.Exec != "" && o.HTTP != "":
+		return nil, fmt.Errorf("exec and http cannot be set together")
+	case o.Exec != "":
+		return NewExec(o.Exec), nil
+	case o.HTTP != "":
+		if !strings.HasPrefix(o.HTTP, "http://") && !strings.HasPrefix(o.HTTP, "https://") {
+			return nil, fmt.Errorf("invalid http endpoint %s, http:// or https:// url is required", o.HTTP)
+		}
+		return NewHTTP(o.HTTP), nil
+	default:
+		return nil, fmt.Errorf("missing handler, set --exec or --http flag")
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecHandler(t *testing.T) {
+	req := &Request{Id: "1", Type: "query", Channel: "orders", Metadata: "get", Body: "o-1"}
+
+	// the request document is a valid response document, cat echoes metadata and body back
+	res, err := NewExec("cat").Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, &Response{Metadata: "get", Body: "o-1"}, res)
+
+	res, err = NewExec(`echo '{"error":"not found"}'`).Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "not found", res.Error)
+
+	res, err = NewExec("true").Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, &Response{}, res)
+
+	_, err = NewExec("echo boom >&2; exit 3").Handle(context.Background(), req)
+	require.EqualError(t, err, "handler failed, exit status 3: boom")
+
+	_, err = NewExec("echo not json").Handle(context.Background(), req)
+	require.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = NewExec("sleep 5").Handle(ctx, req)
+	require.EqualError(t, err, "handler timed out")
+}
+
+func TestBinaryBody(t *testing.T) {
+	binary := []byte{0xff, 0x00, 0xfe, 'a'}
+	req := (&Request{Id: "1", Type: "command"}).SetBody(binary)
+	require.Empty(t, req.Body)
+	require.Equal(t, "/wD+YQ==", req.BodyBase64)
+	require.Equal(t, "text", (&Request{}).SetBody([]byte("text")).Body)
+
+	// cat echoes the base64 body back, the response body is decoded to the original bytes
+	res, err := NewExec("cat").Handle(context.Background(), req)
+	require.NoError(t, err)
+	data, err := res.Data()
+	require.NoError(t, err)
+	require.Equal(t, binary, data)
+
+	_, err = NewExec(`echo '{"body_base64":"not base64!"}'`).Handle(context.Background(), req)
+	require.Error(t, err)
+	_, err = NewExec(`echo '{"body":"a","body_base64":"YQ=="}'`).Handle(context.Background(), req)
+	require.EqualError(t, err, "invalid handler response, body and body_base64 cannot be set together")
+}
+
+func TestHTTPHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &Request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Metadata == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal"))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&Response{Body: "handled " + req.Body, Tags: map[string]string{"type": req.Type}})
+	}))
+	defer server.Close()
+	h := NewHTTP(server.URL)
+
+	res, err := h.Handle(context.Background(), &Request{Id: "1", Type: "command", Body: "o-1"})
+	require.NoError(t, err)
+	require.Equal(t, &Response{Body: "handled o-1", Tags: map[string]string{"type": "command"}}, res)
+
+	_, err = h.Handle(context.Background(), &Request{Id: "2", Metadata: "fail"})
+	require.EqualError(t, err, "handler returned 500 Internal Server Error: internal")
+}
+
+func TestOptions_Handler(t *testing.T) {
+	o := &Options{Concurrency: 1, Timeout: time.Second}
+	_, err := o.Handler()
+	require.Error(t, err)
+	o.Exec, o.HTTP = "cat", "http://localhost:8081"
+	_, err = o.Handler()
+	require.Error(t, err)
+	o.Exec = ""
+	h, err := o.Handler()
+	require.NoError(t, err)
+	require.Equal(t, "http http://localhost:8081", h.String())
+	o.HTTP = "localhost:8081"
+	_, err = o.Handler()
+	require.Error(t, err)
+}
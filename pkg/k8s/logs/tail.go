@@ -67,6 +67,12 @@ func determineColor(podName string) (namespaceColor, podColor, containerColor *c
 	return colors[0], colors[1], colors[1]
 }
 
+// NameColor returns a stable color for a name, names of merged streams are colored the same way pods are
+func NameColor(name string) *color.Color {
+	nameColor, _, _ := determineColor(name)
+	return nameColor
+}
+
 // Start starts tailing
 func (t *Tail) Start(ctx context.Context, i v1.PodInterface) {
 	t.namespaceColor, t.podColor, t.containerColor = determineColor(t.PodName)
@@ -0,0 +1,100 @@
+package latency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Recorder collects latency samples, it is safe for concurrent use
+type Recorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Add(d time.Duration) {
+	r.mu.Lock()
+	r.samples = append(r.samples, d)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.samples)
+}
+
+// Summary returns the samples distribution, percentiles use the nearest rank method
+func (r *Recorder) Summary() *Summary {
+	r.mu.Lock()
+	samples := make([]time.Duration, len(r.samples))
+	copy(samples, r.samples)
+	r.mu.Unlock()
+	s := &Summary{
+		Count: len(samples),
+	}
+	if len(samples) == 0 {
+		return s
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	var total time.Duration
+	for _, d := range samples {
+		total += d
+	}
+	s.Min = samples[0]
+	s.Max = samples[len(samples)-1]
+	s.Mean = total / time.Duration(len(samples))
+	s.P50 = percentile(samples, 50)
+	s.P95 = percentile(samples, 95)
+	s.P99 = percentile(samples, 99)
+	return s
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+type Summary struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// Millis is the summary in milliseconds for json reports
+type Millis struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min_ms"`
+	Mean  float64 `json:"mean_ms"`
+	P50   float64 `json:"p50_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+func (s *Summary) Millis() *Millis {
+	ms := func(d time.Duration) float64 {
+		return math.Round(float64(d)/float64(time.Millisecond)*1000) / 1000
+	}
+	return &Millis{
+		Count: s.Count,
+		Min:   ms(s.Min),
+		Mean:  ms(s.Mean),
+		P50:   ms(s.P50),
+		P95:   ms(s.P95),
+		P99:   ms(s.P99),
+		Max:   ms(s.Max),
+	}
+}
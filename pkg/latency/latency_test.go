@@ -0,0 +1,32 @@
+package latency
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Summary(t *testing.T) {
+	r := NewRecorder()
+	require.Equal(t, &Summary{}, r.Summary())
+	wg := sync.WaitGroup{}
+	for i := 100; i >= 1; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.Add(time.Duration(i) * time.Millisecond)
+		}(i)
+	}
+	wg.Wait()
+	s := r.Summary()
+	require.Equal(t, 100, s.Count)
+	require.Equal(t, time.Millisecond, s.Min)
+	require.Equal(t, 100*time.Millisecond, s.Max)
+	require.Equal(t, 50500*time.Microsecond, s.Mean)
+	require.Equal(t, 50*time.Millisecond, s.P50)
+	require.Equal(t, 95*time.Millisecond, s.P95)
+	require.Equal(t, 99*time.Millisecond, s.P99)
+	require.Equal(t, 99.0, s.Millis().P99)
+}
@@ -0,0 +1,119 @@
+package latency
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type outcome struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+	timeout bool
+}
+
+// Window keeps the outcomes of the requests of the last window duration, it is safe for concurrent use
+type Window struct {
+	mu       sync.Mutex
+	size     time.Duration
+	outcomes []outcome
+}
+
+func NewWindow(size time.Duration) *Window {
+	return &Window{
+		size: size,
+	}
+}
+
+// Add records a successful request latency
+func (w *Window) Add(at time.Time, d time.Duration) {
+	w.add(outcome{at: at, latency: d})
+}
+
+// AddError records a failed request, timeout sets whether the request failed on timeout
+func (w *Window) AddError(at time.Time, timeout bool) {
+	w.add(outcome{at: at, failed: true, timeout: timeout})
+}
+
+func (w *Window) add(o outcome) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.outcomes = append(w.outcomes, o)
+	w.trim(o.at)
+}
+
+func (w *Window) trim(now time.Time) {
+	from := now.Add(-w.size)
+	i := 0
+	for i < len(w.outcomes) && w.outcomes[i].at.Before(from) {
+		i++
+	}
+	w.outcomes = w.outcomes[i:]
+}
+
+// Stats returns the latency distribution of the successful requests and the error rates of the window ending at now
+func (w *Window) Stats(now time.Time) *WindowStats {
+	w.mu.Lock()
+	w.trim(now)
+	outcomes := make([]outcome, len(w.outcomes))
+	copy(outcomes, w.outcomes)
+	w.mu.Unlock()
+	r := NewRecorder()
+	st := &WindowStats{
+		Requests: len(outcomes),
+	}
+	for _, o := range outcomes {
+		switch {
+		case o.timeout:
+			st.Timeouts++
+		case o.failed:
+			st.Errors++
+		default:
+			r.Add(o.latency)
+		}
+	}
+	st.Summary = r.Summary()
+	if st.Requests > 0 {
+		st.ErrorRate = float64(st.Errors+st.Timeouts) * 100 / float64(st.Requests)
+	}
+	return st
+}
+
+// WindowStats are the stats of a window, ErrorRate is the percent of failed and timed out requests
+type WindowStats struct {
+	Requests  int
+	Errors    int
+	Timeouts  int
+	ErrorRate float64
+	Summary   *Summary
+}
+
+// SLO sets the objectives of a window stats, zero objectives are not checked
+type SLO struct {
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	ErrorRate float64
+}
+
+func (s *SLO) IsSet() bool {
+	return s.P50 > 0 || s.P95 > 0 || s.P99 > 0 || s.ErrorRate > 0
+}
+
+// Breaches returns a description of each objective breached by the stats
+func (s *SLO) Breaches(st *WindowStats) []string {
+	var breaches []string
+	check := func(name string, value, max time.Duration) {
+		if max > 0 && st.Summary.Count > 0 && value >= max {
+			breaches = append(breaches, fmt.Sprintf("%s %s >= %s", name, value, max))
+		}
+	}
+	check("p50", st.Summary.P50, s.P50)
+	check("p95", st.Summary.P95, s.P95)
+	check("p99", st.Summary.P99, s.P99)
+	if s.ErrorRate > 0 && st.ErrorRate >= s.ErrorRate {
+		breaches = append(breaches, fmt.Sprintf("error rate %.2f%% >= %.2f%%", st.ErrorRate, s.ErrorRate))
+	}
+	return breaches
+}
@@ -0,0 +1,36 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow_Stats(t *testing.T) {
+	start := time.Now()
+	w := NewWindow(time.Minute)
+	// the first 10 requests fall out of the window
+	for i := 0; i < 10; i++ {
+		w.AddError(start.Add(time.Duration(i)*time.Second), true)
+	}
+	now := start.Add(2 * time.Minute)
+	for i := 1; i <= 98; i++ {
+		w.Add(now.Add(-time.Duration(i)*100*time.Millisecond), time.Duration(i)*time.Millisecond)
+	}
+	w.AddError(now, false)
+	w.AddError(now, true)
+	st := w.Stats(now)
+	require.Equal(t, 100, st.Requests)
+	require.Equal(t, 1, st.Errors)
+	require.Equal(t, 1, st.Timeouts)
+	require.Equal(t, 2.0, st.ErrorRate)
+	require.Equal(t, 98, st.Summary.Count)
+	require.Equal(t, 98*time.Millisecond, st.Summary.Max)
+
+	require.Empty(t, (&SLO{P99: 200 * time.Millisecond, ErrorRate: 5}).Breaches(st))
+	require.Equal(t, []string{"p99 98ms >= 50ms", "error rate 2.00% >= 1.00%"}, (&SLO{P99: 50 * time.Millisecond, ErrorRate: 1}).Breaches(st))
+	require.False(t, (&SLO{}).IsSet())
+
+	require.Equal(t, 0, w.Stats(now.Add(2*time.Minute)).Requests)
+}
@@ -83,6 +83,37 @@ type Metric struct {
 	}
 }
 
+// stats api kind and sub kind ids of rpc channels
+const (
+	KindRPC         = "3"
+	SubKindCommands = "3"
+	SubKindQueries  = "4"
+)
+
+// GetMetrics returns the charts and channels stats of a kind and sub kind of channels, the time frame, i.e. 1h, is
+// the server default when empty
+func GetMetrics(ctx context.Context, cfg *config.Config, kind, subKind, timeFrame string) (*Metric, error) {
+	metrics := &Metric{}
+	req := resty.New().R().SetContext(ctx).SetResult(metrics).SetError(metrics).
+		SetQueryParam("node", fmt.Sprintf("%s-0", cfg.CurrentStatefulSet)).
+		SetQueryParam("kind_id", kind).
+		SetQueryParam("sub_kind_id", subKind)
+	if timeFrame != "" {
+		req.SetQueryParam("time_frame", timeFrame)
+	}
+	r, err := req.Get(fmt.Sprintf("%s/v1/stats/sub_kind", cfg.GetApiHttpURI()))
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsSuccess() {
+		return nil, fmt.Errorf("not available in current Kubemq version, consider upgrade Kubemq version")
+	}
+	if metrics.Error {
+		return nil, fmt.Errorf(metrics.ErrorString)
+	}
+	return metrics, nil
+}
+
 func PrintMetrics(ctx context.Context, out io.Writer, cfg *config.Config, kind, subkind string, timeframe string, top int) error {
 	metrics := &Metric{}
 	req := resty.New().R().SetResult(metrics).SetError(metrics).SetQueryParam("node", "kubemq-cluster-0").SetQueryParam("kind_id", kind).SetQueryParam("sub_kind_id", subkind).SetQueryParam("time_frame", timeframe)
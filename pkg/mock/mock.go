@@ -0,0 +1,260 @@
+// Package mock answers 'queries' and 'commands' requests from a rules file to stub RPC services
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	kubemq2 "github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemqctl/pkg/generator"
+	"github.com/kubemq-io/kubemqctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Request is a received request, it is the dot of response templates, i.e. {{.Metadata}} or {{.JSONPath "$.id"}}
+type Request struct {
+	Id       string
+	Channel  string
+	Metadata string
+	Body     string
+	Tags     map[string]string
+}
+
+// JSONPath returns the values selected in the request body joined by commas, empty string is returned when the body
+// is not json or nothing is selected
+func (r *Request) JSONPath(expr string) (string, error) {
+	jp, err := utils.NewJSONPath(expr)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(jp.Values([]byte(r.Body)), ","), nil
+}
+
+// Response is the answer of a rule, a non empty Error is sent as a failed (not executed) response
+type Response struct {
+	Rule     string
+	Body     []byte
+	Metadata string
+	Error    string
+	Delay    time.Duration
+}
+
+// Apply sets the response fields on a kubemq response, responses with an error are sent as not executed
+func (r *Response) Apply(resp *kubemq2.Response) *kubemq2.Response {
+	resp.SetMetadata(r.Metadata).SetBody(r.Body)
+	if r.Error != "" {
+		return resp.SetError(errors.New(r.Error))
+	}
+	return resp.SetExecutedAt(time.Now())
+}
+
+// Rules is the rules file, the first rule matching a request answers it
+//
+//	rules:
+//	  - name: get-order
+//	    match:
+//	      metadata: ^get$
+//	      body: order
+//	      jsonpath:
+//	        - path: $.type
+//	          value: ^order$
+//	    response:
+//	      body: '{"id":"{{.JSONPath "$.id"}}","at":"{{now}}"}'
+//	      metadata: ok
+//	      delay: 200ms
+//	  - name: fail-everything-else
+//	    response:
+//	      error: no such order
+type Rules struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Name     string        `yaml:"name"`
+	Match    *Match        `yaml:"match"`
+	Response *ResponseSpec `yaml:"response"`
+
+	metadata *regexp.Regexp
+	body     *regexp.Regexp
+	paths    []*pathMatcher
+	tmplBody *generator.Template
+	tmplMeta *generator.Template
+	tmplErr  *generator.Template
+	delay    time.Duration
+	hits     int
+}
+
+// Match sets the regular expressions a request must match, all set expressions must match
+type Match struct {
+	Metadata string       `yaml:"metadata"`
+	Body     string       `yaml:"body"`
+	JSONPath []*PathMatch `yaml:"jsonpath"`
+}
+
+// PathMatch matches when any value selected by Path in the json body matches the Value regular expression
+type PathMatch struct {
+	Path  string `yaml:"path"`
+	Value string `yaml:"value"`
+}
+
+// ResponseSpec sets the templated body, metadata and error of a response and how long to delay it
+type ResponseSpec struct {
+	Body     string `yaml:"body"`
+	Metadata string `yaml:"metadata"`
+	Error    string `yaml:"error"`
+	Delay    string `yaml:"delay"`
+}
+
+type pathMatcher struct {
+	path  *utils.JSONPath
+	value *regexp.Regexp
+}
+
+// Responder answers requests with the first matching rule
+type Responder struct {
+	mu    sync.Mutex
+	rules []*Rule
+}
+
+func Load(file string) (*Responder, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("load mock rules file %s, %s", file, err.Error())
+	}
+	r, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("load mock rules file %s, %s", file, err.Error())
+	}
+	return r, nil
+}
+
+func Parse(data []byte) (*Responder, error) {
+	rules := &Rules{}
+	if err := yaml.UnmarshalStrict(data, rules); err != nil {
+		return nil, err
+	}
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("no rules are set")
+	}
+	for i, rule := range rules.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s, %s", rule.Name, err.Error())
+		}
+	}
+	return &Responder{
+		rules: rules.Rules,
+	}, nil
+}
+
+func (r *Rule) compile() error {
+	var err error
+	if r.Match != nil {
+		if r.metadata, err = compileRegex(r.Match.Metadata); err != nil {
+			return fmt.Errorf("invalid metadata regex, %s", err.Error())
+		}
+		if r.body, err = compileRegex(r.Match.Body); err != nil {
+			return fmt.Errorf("invalid body regex, %s", err.Error())
+		}
+		for _, item := range r.Match.JSONPath {
+			pm := &pathMatcher{}
+			if pm.path, err = utils.NewJSONPath(item.Path); err != nil {
+				return err
+			}
+			if pm.value, err = regexp.Compile(item.Value); err != nil {
+				return fmt.Errorf("invalid jsonpath %s value regex, %s", item.Path, err.Error())
+			}
+			r.paths = append(r.paths, pm)
+		}
+	}
+	if r.Response == nil {
+		r.Response = &ResponseSpec{}
+	}
+	if r.tmplBody, err = generator.New("body", r.Response.Body); err != nil {
+		return err
+	}
+	if r.tmplMeta, err = generator.New("metadata", r.Response.Metadata); err != nil {
+		return err
+	}
+	if r.tmplErr, err = generator.New("error", r.Response.Error); err != nil {
+		return err
+	}
+	if r.Response.Delay != "" {
+		if r.delay, err = time.ParseDuration(r.Response.Delay); err != nil {
+			return fmt.Errorf("invalid delay, %s", err.Error())
+		}
+	}
+	return nil
+}
+
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
+
+func (r *Rule) match(req *Request) bool {
+	if r.metadata != nil && !r.metadata.MatchString(req.Metadata) {
+		return false
+	}
+	if r.body != nil && !r.body.MatchString(req.Body) {
+		return false
+	}
+	for _, pm := range r.paths {
+		matched := false
+		for _, value := range pm.path.Values([]byte(req.Body)) {
+			if pm.value.MatchString(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Respond returns the response of the first rule matching the request, nil is returned when no rule matches. The
+// seq template function returns how many requests the rule answered, including this one
+func (r *Responder) Respond(req *Request) (*Response, error) {
+	r.mu.Lock()
+	var rule *Rule
+	for _, item := range r.rules {
+		if item.match(req) {
+			rule = item
+			rule.hits++
+			break
+		}
+	}
+	if rule == nil {
+		r.mu.Unlock()
+		return nil, nil
+	}
+	seq := rule.hits
+	r.mu.Unlock()
+	res := &Response{
+		Rule:  rule.Name,
+		Delay: rule.delay,
+	}
+	body, err := rule.tmplBody.Execute(seq, req)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s, %s", rule.Name, err.Error())
+	}
+	res.Body = []byte(body)
+	if res.Metadata, err = rule.tmplMeta.Execute(seq, req); err != nil {
+		return nil, fmt.Errorf("rule %s, %s", rule.Name, err.Error())
+	}
+	if res.Error, err = rule.tmplErr.Execute(seq, req); err != nil {
+		return nil, fmt.Errorf("rule %s, %s", rule.Name, err.Error())
+	}
+	return res, nil
+}
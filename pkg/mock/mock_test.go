@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRules = `
+rules:
+  - name: get-order
+    match:
+      metadata: ^get$
+      jsonpath:
+        - path: $.type
+          value: ^order$
+    response:
+      body: '{"id":"{{.JSONPath "$.id"}}","n":{{seq}},"channel":"{{.Channel}}"}'
+      metadata: ok
+      delay: 200ms
+  - name: slow-down
+    match:
+      body: slow
+    response:
+      error: service unavailable for {{.Metadata}}
+  - response:
+      body: default
+`
+
+func TestResponder_Respond(t *testing.T) {
+	r, err := Parse([]byte(testRules))
+	require.NoError(t, err)
+
+	res, err := r.Respond(&Request{Channel: "orders", Metadata: "get", Body: `{"type":"order","id":"o-1"}`})
+	require.NoError(t, err)
+	require.Equal(t, "get-order", res.Rule)
+	require.Equal(t, `{"id":"o-1","n":1,"channel":"orders"}`, string(res.Body))
+	require.Equal(t, "ok", res.Metadata)
+	require.Empty(t, res.Error)
+	require.Equal(t, 200*time.Millisecond, res.Delay)
+
+	res, err = r.Respond(&Request{Channel: "orders", Metadata: "get", Body: `{"type":"order","id":"o-2"}`})
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"o-2","n":2,"channel":"orders"}`, string(res.Body))
+
+	res, err = r.Respond(&Request{Metadata: "put", Body: "slow request"})
+	require.NoError(t, err)
+	require.Equal(t, "slow-down", res.Rule)
+	require.Equal(t, "service unavailable for put", res.Error)
+
+	res, err = r.Respond(&Request{Metadata: "get", Body: `{"type":"invoice"}`})
+	require.NoError(t, err)
+	require.Equal(t, "rule-3", res.Rule)
+	require.Equal(t, "default", string(res.Body))
+}
+
+func TestResponder_NoMatch(t *testing.T) {
+	r, err := Parse([]byte(`
+rules:
+  - match:
+      metadata: ^get$
+`))
+	require.NoError(t, err)
+	res, err := r.Respond(&Request{Metadata: "put"})
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, data := range []string{
+		``,
+		`rules: [{match: {metadata: "("}}]`,
+		`rules: [{match: {jsonpath: [{path: "$.a[", value: "x"}]}}]`,
+		`rules: [{response: {delay: "soon"}}]`,
+		`rules: [{response: {body: "{{.Body"}}]`,
+		`rules: [{respond: {body: "typo"}}]`,
+	} {
+		_, err := Parse([]byte(data))
+		require.Error(t, err, data)
+	}
+}
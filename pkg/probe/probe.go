@@ -0,0 +1,153 @@
+// Package probe sends synthetic requests at a fixed interval and checks their rolling latency and error rate
+// against an SLO
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kubemq-io/kubemqctl/pkg/latency"
+	"github.com/spf13/cobra"
+)
+
+// Request sends one probe request with the request timeout, an error is returned for failed or not executed requests
+type Request func(ctx context.Context, timeout time.Duration) error
+
+// Options are the probe flags of 'commands' and 'queries' probe commands
+type Options struct {
+	Body         string
+	Metadata     string
+	Interval     time.Duration
+	Timeout      time.Duration
+	Window       time.Duration
+	Count        int
+	MinSamples   int
+	ExitOnBreach bool
+	SLO          latency.SLO
+}
+
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.Body, "body", "", "probe", "set probe request body")
+	cmd.PersistentFlags().StringVarP(&o.Metadata, "metadata", "m", "kubemqctl-probe", "set probe request metadata")
+	cmd.PersistentFlags().DurationVarP(&o.Interval, "interval", "i", time.Second, "set interval between probe requests")
+	cmd.PersistentFlags().DurationVarP(&o.Timeout, "timeout", "t", time.Second, "set probe request timeout")
+	cmd.PersistentFlags().DurationVarP(&o.Window, "window", "w", time.Minute, "set rolling window of the latency percentiles and error rate")
+	cmd.PersistentFlags().IntVarP(&o.Count, "count", "n", 0, "set how many probe requests to send before exiting, 0 probes until interrupted")
+	cmd.PersistentFlags().IntVarP(&o.MinSamples, "min-samples", "", 10, "set how many requests the window must hold before the SLO is checked while probing")
+	cmd.PersistentFlags().BoolVarP(&o.ExitOnBreach, "exit-on-breach", "", false, "set exit with non-zero code as soon as the SLO is breached")
+	cmd.PersistentFlags().DurationVarP(&o.SLO.P50, "slo-p50", "", 0, "set SLO p50 latency objective, i.e. 50ms, 0 disables the objective")
+	cmd.PersistentFlags().DurationVarP(&o.SLO.P95, "slo-p95", "", 0, "set SLO p95 latency objective, i.e. 100ms, 0 disables the objective")
+	cmd.PersistentFlags().DurationVarP(&o.SLO.P99, "slo-p99", "", 0, "set SLO p99 latency objective, i.e. 200ms, 0 disables the objective")
+	cmd.PersistentFlags().Float64VarP(&o.SLO.ErrorRate, "slo-error-rate", "", 0, "set SLO error and timeout rate percent objective, i.e. 1, 0 disables the objective")
+}
+
+func (o *Options) Validate() error {
+	if o.Interval <= 0 {
+		return fmt.Errorf("interval must be a positive duration")
+	}
+	if o.Timeout < time.Second {
+		return fmt.Errorf("timeout must be at least 1 second")
+	}
+	if o.Window < o.Interval {
+		return fmt.Errorf("window must be at least the interval")
+	}
+	if o.Count < 0 {
+		return fmt.Errorf("count must be zero or a positive number")
+	}
+	if o.MinSamples < 1 {
+		return fmt.Errorf("min-samples must be a positive number")
+	}
+	if o.SLO.P50 < 0 || o.SLO.P95 < 0 || o.SLO.P99 < 0 || o.SLO.ErrorRate < 0 || o.SLO.ErrorRate > 100 {
+		return fmt.Errorf("invalid SLO, latency objectives must be positive durations and error rate a percent")
+	}
+	if o.ExitOnBreach && !o.SLO.IsSet() {
+		return fmt.Errorf("exit-on-breach requires at least one SLO objective")
+	}
+	return nil
+}
+
+// Run sends a probe request every interval until count requests were sent or ctx is done, and prints each result with
+// the window stats. The SLO is checked after each request once the window holds min samples and at the end of the
+// run, an error is returned when the SLO is breached at the end of the run or, with exit on breach, as soon as it is
+// breached
+func Run(ctx context.Context, o *Options, out io.Writer, send Request) error {
+	w := latency.NewWindow(o.Window)
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+	alert := color.New(color.FgHiRed).SprintFunc()
+	var breaches []string
+	for i := 1; o.Count == 0 || i <= o.Count; i++ {
+		if i > 1 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return finish(out, o, w.Stats(time.Now()))
+			}
+		}
+		start := time.Now()
+		// the server fails requests on the request timeout, the context guards against a lost response
+		reqCtx, cancel := context.WithTimeout(ctx, o.Timeout+time.Second)
+		err := send(reqCtx, o.Timeout)
+		cancel()
+		elapsed := time.Since(start)
+		if ctx.Err() != nil {
+			return finish(out, o, w.Stats(time.Now()))
+		}
+		result := fmt.Sprintf("ok %s", elapsed.Round(time.Microsecond))
+		if err != nil {
+			timeout := elapsed >= o.Timeout || errors.Is(err, context.DeadlineExceeded)
+			w.AddError(start, timeout)
+			if timeout {
+				result = alert("timeout")
+			} else {
+				result = alert(fmt.Sprintf("error: %s", err.Error()))
+			}
+		} else {
+			w.Add(start, elapsed)
+		}
+		st := w.Stats(time.Now())
+		fmt.Fprintf(out, "%s probe %d %s | %s\n", start.Format("15:04:05"), i, result, windowLine(o, st))
+		current := breaches
+		if st.Requests >= o.MinSamples || i == o.Count {
+			current = o.SLO.Breaches(st)
+		}
+		switch {
+		case len(current) > 0 && len(breaches) == 0:
+			fmt.Fprintln(out, alert(fmt.Sprintf("SLO breached: %s", strings.Join(current, ", "))))
+		case len(current) == 0 && len(breaches) > 0:
+			fmt.Fprintln(out, "SLO recovered")
+		}
+		breaches = current
+		if len(breaches) > 0 && o.ExitOnBreach {
+			return fmt.Errorf("SLO breached, %s", strings.Join(breaches, ", "))
+		}
+	}
+	return finish(out, o, w.Stats(time.Now()))
+}
+
+// finish prints the summary of the run and returns an error when the final window breaches the SLO
+func finish(out io.Writer, o *Options, st *latency.WindowStats) error {
+	printSummary(out, o, st)
+	if st.Requests == 0 {
+		return nil
+	}
+	if breaches := o.SLO.Breaches(st); len(breaches) > 0 {
+		return fmt.Errorf("SLO breached, %s", strings.Join(breaches, ", "))
+	}
+	return nil
+}
+
+func windowLine(o *Options, st *latency.WindowStats) string {
+	ms := st.Summary.Millis()
+	return fmt.Sprintf("window %s: requests %d, p50 %.2fms, p95 %.2fms, p99 %.2fms, errors %d, timeouts %d, error rate %.2f%%",
+		o.Window, st.Requests, ms.P50, ms.P95, ms.P99, st.Errors, st.Timeouts, st.ErrorRate)
+}
+
+func printSummary(out io.Writer, o *Options, st *latency.WindowStats) {
+	fmt.Fprintf(out, "Summary: %s\n", windowLine(o, st))
+}
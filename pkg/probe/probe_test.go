@@ -0,0 +1,91 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemqctl/pkg/latency"
+	"github.com/stretchr/testify/require"
+)
+
+func testOptions() *Options {
+	return &Options{
+		Interval:   time.Millisecond,
+		Timeout:    time.Second,
+		Window:     time.Minute,
+		Count:      10,
+		MinSamples: 5,
+	}
+}
+
+func TestRun(t *testing.T) {
+	calls := 0
+	send := func(ctx context.Context, timeout time.Duration) error {
+		calls++
+		if calls == 3 {
+			return fmt.Errorf("not executed")
+		}
+		return nil
+	}
+
+	o := testOptions()
+	o.SLO = latency.SLO{P99: time.Second, ErrorRate: 20}
+	out := &bytes.Buffer{}
+	require.NoError(t, Run(context.Background(), o, out, send))
+	require.Equal(t, 10, calls)
+	require.Contains(t, out.String(), "probe 3 error: not executed")
+	require.Contains(t, out.String(), "Summary: window 1m0s: requests 10")
+	require.Contains(t, out.String(), "error rate 10.00%")
+
+	calls = 0
+	o.SLO = latency.SLO{ErrorRate: 5}
+	out.Reset()
+	require.EqualError(t, Run(context.Background(), o, out, send), "SLO breached, error rate 10.00% >= 5.00%")
+	require.Contains(t, out.String(), "SLO breached: error rate")
+
+	// the SLO is checked while probing once the window holds min samples
+	calls = 0
+	o.ExitOnBreach = true
+	out.Reset()
+	require.Error(t, Run(context.Background(), o, out, send))
+	require.Equal(t, 5, calls)
+}
+
+func TestRun_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	send := func(ctx context.Context, timeout time.Duration) error {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return fmt.Errorf("not executed")
+	}
+	// continuous runs end on interrupt with the summary and the final window SLO check
+	o := testOptions()
+	o.Count = 0
+	o.SLO = latency.SLO{ErrorRate: 5}
+	out := &bytes.Buffer{}
+	require.EqualError(t, Run(ctx, o, out, send), "SLO breached, error rate 100.00% >= 5.00%")
+	require.Equal(t, 3, calls)
+	require.Contains(t, out.String(), "Summary: window 1m0s: requests 2")
+
+	o.SLO = latency.SLO{}
+	require.NoError(t, Run(ctx, o, out, send))
+}
+
+func TestOptions_Validate(t *testing.T) {
+	require.NoError(t, testOptions().Validate())
+	o := testOptions()
+	o.ExitOnBreach = true
+	require.Error(t, o.Validate())
+	o = testOptions()
+	o.Window = 0
+	require.Error(t, o.Validate())
+	o = testOptions()
+	o.SLO.ErrorRate = 101
+	require.Error(t, o.Validate())
+}
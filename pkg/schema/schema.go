@@ -0,0 +1,155 @@
+// Package schema validates message bodies against JSON Schema documents
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/spf13/cobra"
+)
+
+// Validator validates message bodies against a JSON Schema, draft 2020-12 is used unless the schema sets $schema
+type Validator struct {
+	file   string
+	schema *jsonschema.Schema
+}
+
+func Load(file string) (*Validator, error) {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	s, err := c.Compile(file)
+	if err != nil {
+		return nil, fmt.Errorf("load schema %s, %s", file, err.Error())
+	}
+	return &Validator{
+		file:   file,
+		schema: s,
+	}, nil
+}
+
+func (v *Validator) File() string {
+	return v.file
+}
+
+// Validate returns an error describing why the body is not a json document valid against the schema
+func (v *Validator) Validate(body []byte) error {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("body is not valid json, %s", err.Error())
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("body is not valid json, unexpected data after json value")
+	}
+	if err := v.schema.Validate(doc); err != nil {
+		return fmt.Errorf("body does not match schema %s, %s", v.file, validationMessage(err))
+	}
+	return nil
+}
+
+// Error returns the validation error message of a body, empty string is returned for valid bodies or nil validator
+func Error(v *Validator, body []byte) string {
+	if v == nil {
+		return ""
+	}
+	if err := v.Validate(body); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// validationMessage flattens the validation errors tree into a single line of the leaf errors
+func validationMessage(err error) string {
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err.Error()
+	}
+	var leaves []string
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			location := e.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			leaves = append(leaves, fmt.Sprintf("%s: %s", location, e.Message))
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(ve)
+	return strings.Join(leaves, "; ")
+}
+
+// Options are the schema flags of commands which send or receive messages
+type Options struct {
+	Schema string
+	cache  map[string]*Validator
+}
+
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.Schema, "schema", "", "", "set JSON Schema (draft 2020-12) file to validate message bodies, default is the channel schema set in the config file 'schemas' mapping")
+}
+
+// Validator returns the validator of a channel, the --schema flag takes precedence over the config file channel
+// mapping, nil is returned when no schema is set for the channel
+func (o *Options) Validator(cfg *config.Config, channel string) (*Validator, error) {
+	if o == nil {
+		return nil, nil
+	}
+	file := o.Schema
+	if file == "" && cfg != nil {
+		file = channelSchema(cfg.Schemas, channel)
+	}
+	if file == "" {
+		return nil, nil
+	}
+	if v, ok := o.cache[file]; ok {
+		return v, nil
+	}
+	v, err := Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if o.cache == nil {
+		o.cache = map[string]*Validator{}
+	}
+	o.cache[file] = v
+	return v, nil
+}
+
+// ValidateBody validates a body sent to a channel, nil is returned when no schema is set for the channel
+func (o *Options) ValidateBody(cfg *config.Config, channel string, body []byte) error {
+	v, err := o.Validator(cfg, channel)
+	if err != nil || v == nil {
+		return err
+	}
+	return v.Validate(body)
+}
+
+// channelSchema returns the schema file set for a channel name or glob pattern, an exact name takes precedence over
+// patterns and longer patterns take precedence over shorter ones
+func channelSchema(schemas []*config.ChannelSchema, channel string) string {
+	var best *config.ChannelSchema
+	for _, item := range schemas {
+		if item.Channel == channel {
+			return item.Schema
+		}
+		if ok, _ := path.Match(item.Channel, channel); ok && (best == nil || len(item.Channel) > len(best.Channel)) {
+			best = item
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Schema
+}
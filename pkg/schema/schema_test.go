@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemq-io/kubemqctl/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "amount"],
+	"properties": {
+		"id": {"type": "string"},
+		"amount": {"type": "number", "minimum": 0}
+	}
+}`
+
+func TestValidator_Validate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "order.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(orderSchema), 0644))
+	v, err := Load(file)
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "valid", body: `{"id":"o-1","amount":10.5}`},
+		{name: "missing property", body: `{"id":"o-1"}`, wantErr: "missing properties: 'amount'"},
+		{name: "invalid value", body: `{"id":"o-1","amount":-1}`, wantErr: "/amount: must be >= 0"},
+		{name: "not json", body: `some text`, wantErr: "body is not valid json"},
+		{name: "trailing data", body: `{"id":"o-1","amount":1} {}`, wantErr: "unexpected data after json value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate([]byte(tt.body))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				require.Empty(t, Error(v, []byte(tt.body)))
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+			require.Equal(t, err.Error(), Error(v, []byte(tt.body)))
+		})
+	}
+	require.Empty(t, Error(nil, []byte("not json")))
+}
+
+func TestOptions_Validator(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "order.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(orderSchema), 0644))
+	cfg := &config.Config{Schemas: []*config.ChannelSchema{
+		{Channel: "orders.*", Schema: file},
+		{Channel: "orders.created", Schema: filepath.Join(dir, "created.json")},
+		{Channel: "orders.eu.*", Schema: filepath.Join(dir, "eu.json")},
+	}}
+	require.Equal(t, filepath.Join(dir, "created.json"), channelSchema(cfg.Schemas, "orders.created"))
+	require.Equal(t, filepath.Join(dir, "eu.json"), channelSchema(cfg.Schemas, "orders.eu.berlin"))
+	require.Equal(t, file, channelSchema(cfg.Schemas, "orders.updated"))
+	require.Equal(t, "", channelSchema(cfg.Schemas, "billing"))
+
+	o := &Options{}
+	v, err := o.Validator(cfg, "orders.updated")
+	require.NoError(t, err)
+	require.Equal(t, file, v.File())
+	v, err = o.Validator(cfg, "billing")
+	require.NoError(t, err)
+	require.Nil(t, v)
+	_, err = o.Validator(cfg, "orders.created")
+	require.Error(t, err)
+
+	var none *Options
+	v, err = none.Validator(cfg, "orders.updated")
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
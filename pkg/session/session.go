@@ -0,0 +1,316 @@
+// Package session records request-reply exchanges of RPC channels to ndjson files and compares replayed responses
+// with the recorded ones
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Exchange is a recorded request and its response, Response is nil when no response was seen for the request
+type Exchange struct {
+	RequestId   string            `json:"request_id"`
+	Channel     string            `json:"channel"`
+	Metadata    string            `json:"metadata,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	TimeoutMs   int64             `json:"timeout_ms,omitempty"`
+	RequestedAt time.Time         `json:"requested_at"`
+	Response    *Response         `json:"response,omitempty"`
+	LatencyMs   float64           `json:"latency_ms,omitempty"`
+}
+
+// Response is a recorded or replayed response
+type Response struct {
+	Executed bool              `json:"executed"`
+	Error    string            `json:"error,omitempty"`
+	Metadata string            `json:"metadata,omitempty"`
+	Body     []byte            `json:"body,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	CacheHit bool              `json:"cache_hit,omitempty"`
+}
+
+// attached is an attached request or response message, keys are matched ignoring case and underscores so both
+// RequestID and request_id are read
+type attached map[string]json.RawMessage
+
+func parseAttached(msg string) (attached, bool) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(msg), &raw); err != nil {
+		return nil, false
+	}
+	a := attached{}
+	for key, value := range raw {
+		a[strings.ToLower(strings.Replace(key, "_", "", -1))] = value
+	}
+	return a, true
+}
+
+func (a attached) has(keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := a[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a attached) string(keys ...string) string {
+	for _, key := range keys {
+		var s string
+		if err := json.Unmarshal(a[key], &s); err == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func (a attached) bool(key string) bool {
+	var b bool
+	_ = json.Unmarshal(a[key], &b)
+	return b
+}
+
+func (a attached) int(key string) int64 {
+	var n int64
+	_ = json.Unmarshal(a[key], &n)
+	return n
+}
+
+func (a attached) tags() map[string]string {
+	tags := map[string]string{}
+	if err := json.Unmarshal(a["tags"], &tags); err != nil || len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// body returns the message body, []byte bodies are marshaled as base64 strings and json bodies are kept as is
+func (a attached) body() []byte {
+	value, ok := a["body"]
+	if !ok || string(value) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return value
+	}
+	if dec, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return dec
+	}
+	return []byte(s)
+}
+
+// isResponse returns true for response messages, only responses carry the execution result fields
+func (a attached) isResponse() bool {
+	return a.has("executed", "executedat", "timestamp", "cachehit")
+}
+
+// Recorder pairs attached requests and responses by request id
+type Recorder struct {
+	wait    time.Duration
+	pending map[string]*Exchange
+}
+
+// NewRecorder returns a recorder which waits for the response of a request up to the request timeout, or wait when
+// the request has no timeout
+func NewRecorder(wait time.Duration) *Recorder {
+	return &Recorder{
+		wait:    wait,
+		pending: map[string]*Exchange{},
+	}
+}
+
+// Add adds an attached message seen at a time, the exchange completed by a response message is returned
+func (r *Recorder) Add(channel, msg string, at time.Time) *Exchange {
+	a, ok := parseAttached(msg)
+	if !ok {
+		return nil
+	}
+	id := a.string("requestid", "id")
+	if id == "" {
+		return nil
+	}
+	if a.isResponse() {
+		ex, ok := r.pending[id]
+		if !ok {
+			return nil
+		}
+		delete(r.pending, id)
+		ex.Response = &Response{
+			Executed: a.bool("executed"),
+			Error:    a.string("error"),
+			Metadata: a.string("metadata"),
+			Body:     a.body(),
+			Tags:     a.tags(),
+			CacheHit: a.bool("cachehit"),
+		}
+		ex.LatencyMs = float64(at.Sub(ex.RequestedAt).Microseconds()) / 1000
+		return ex
+	}
+	if a.string("channel") != "" {
+		channel = a.string("channel")
+	}
+	r.pending[id] = &Exchange{
+		RequestId:   id,
+		Channel:     channel,
+		Metadata:    a.string("metadata"),
+		Body:        a.body(),
+		Tags:        a.tags(),
+		TimeoutMs:   a.int("timeout"),
+		RequestedAt: at,
+	}
+	return nil
+}
+
+// Expire returns the requests which waited for a response longer than their timeout, sorted by request time
+func (r *Recorder) Expire(now time.Time) []*Exchange {
+	var expired []*Exchange
+	for id, ex := range r.pending {
+		wait := r.wait
+		if ex.TimeoutMs > 0 {
+			wait = time.Duration(ex.TimeoutMs)*time.Millisecond + time.Second
+		}
+		if now.Sub(ex.RequestedAt) > wait {
+			expired = append(expired, ex)
+			delete(r.pending, id)
+		}
+	}
+	sortExchanges(expired)
+	return expired
+}
+
+// Flush returns all the requests waiting for a response, sorted by request time
+func (r *Recorder) Flush() []*Exchange {
+	var list []*Exchange
+	for _, ex := range r.pending {
+		list = append(list, ex)
+	}
+	r.pending = map[string]*Exchange{}
+	sortExchanges(list)
+	return list
+}
+
+func sortExchanges(list []*Exchange) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].RequestedAt.Before(list[j].RequestedAt)
+	})
+}
+
+// Write writes an exchange as a session file line
+func Write(w io.Writer, ex *Exchange) error {
+	data, err := json.Marshal(ex)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Read reads the exchanges of a session file
+func Read(file string) ([]*Exchange, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("open session file %s, %s", file, err.Error())
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func read(r io.Reader) ([]*Exchange, error) {
+	var list []*Exchange
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		ex := &Exchange{}
+		if err := json.Unmarshal(data, ex); err != nil {
+			return nil, fmt.Errorf("invalid session line %d, %s", line, err.Error())
+		}
+		list = append(list, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Fields are the response fields compared by Diff
+var Fields = []string{"executed", "error", "metadata", "body", "tags"}
+
+// Diff returns a description of each compared field which differs between the recorded and replayed responses, json
+// bodies are compared by value so key order and formatting differences are ignored
+func Diff(recorded, replayed *Response, fields []string) []string {
+	var diffs []string
+	add := func(field, rec, rep string) {
+		diffs = append(diffs, fmt.Sprintf("%s: recorded %s, replayed %s", field, rec, rep))
+	}
+	for _, field := range fields {
+		switch field {
+		case "executed":
+			if recorded.Executed != replayed.Executed {
+				add(field, fmt.Sprint(recorded.Executed), fmt.Sprint(replayed.Executed))
+			}
+		case "error":
+			if recorded.Error != replayed.Error {
+				add(field, fmt.Sprintf("%q", recorded.Error), fmt.Sprintf("%q", replayed.Error))
+			}
+		case "metadata":
+			if recorded.Metadata != replayed.Metadata {
+				add(field, fmt.Sprintf("%q", recorded.Metadata), fmt.Sprintf("%q", replayed.Metadata))
+			}
+		case "body":
+			rec, rep := normalizeBody(recorded.Body), normalizeBody(replayed.Body)
+			if rec != rep {
+				add(field, rec, rep)
+			}
+		case "tags":
+			rec, _ := json.Marshal(recorded.Tags)
+			rep, _ := json.Marshal(replayed.Tags)
+			if len(recorded.Tags)+len(replayed.Tags) > 0 && string(rec) != string(rep) {
+				add(field, string(rec), string(rep))
+			}
+		}
+	}
+	return diffs
+}
+
+// normalizeBody returns compact json of json bodies and the quoted body otherwise
+func normalizeBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprintf("%q", string(body))
+}
+
+// ValidateFields returns an error for fields Diff does not compare
+func ValidateFields(fields []string) error {
+	for _, field := range fields {
+		found := false
+		for _, f := range Fields {
+			if f == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid compare field %s, fields are %s", field, strings.Join(Fields, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Now()
+	r := NewRecorder(time.Minute)
+	require.Nil(t, r.Add("q1", `{"RequestID":"1","Metadata":"m","Body":"aGVsbG8=","Timeout":2000}`, start))
+	require.Nil(t, r.Add("q1", `{"request_id":"2","body":{"a":1}}`, start.Add(time.Millisecond)))
+	require.Nil(t, r.Add("q1", `not json`, start))
+	require.Nil(t, r.Add("q1", `{"RequestID":"3","Executed":true}`, start))
+
+	ex := r.Add("q1", `{"RequestID":"1","Executed":true,"Body":"d29ybGQ=","CacheHit":true}`, start.Add(5*time.Millisecond))
+	require.NotNil(t, ex)
+	require.Equal(t, "q1", ex.Channel)
+	require.Equal(t, "m", ex.Metadata)
+	require.Equal(t, []byte("hello"), ex.Body)
+	require.Equal(t, int64(2000), ex.TimeoutMs)
+	require.Equal(t, &Response{Executed: true, Body: []byte("world"), CacheHit: true}, ex.Response)
+	require.Equal(t, 5.0, ex.LatencyMs)
+
+	// requests without timeout wait up to the recorder wait
+	require.Empty(t, r.Expire(start.Add(30*time.Second)))
+	expired := r.Expire(start.Add(2 * time.Minute))
+	require.Len(t, expired, 1)
+	require.Equal(t, `{"a":1}`, string(expired[0].Body))
+	require.Nil(t, expired[0].Response)
+	require.Empty(t, r.Flush())
+}
+
+func TestWriteRead(t *testing.T) {
+	buf := &bytes.Buffer{}
+	in := &Exchange{
+		RequestId:   "1",
+		Channel:     "q1",
+		Body:        []byte("hello"),
+		RequestedAt: time.Now().UTC().Round(time.Millisecond),
+		Response:    &Response{Executed: true, Body: []byte("world")},
+	}
+	require.NoError(t, Write(buf, in))
+	require.NoError(t, Write(buf, &Exchange{RequestId: "2"}))
+	buf.WriteString("\n")
+	list, err := read(buf)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, in, list[0])
+
+	_, err = read(bytes.NewBufferString("{}\nbad\n"))
+	require.EqualError(t, err, "invalid session line 2, invalid character 'b' looking for beginning of value")
+}
+
+func TestDiff(t *testing.T) {
+	recorded := &Response{Executed: true, Metadata: "m", Body: []byte(`{"a":1,"b":[1,2]}`)}
+	require.Empty(t, Diff(recorded, &Response{Executed: true, Metadata: "m", Body: []byte(`{ "b": [1, 2], "a": 1 }`)}, Fields))
+	require.Equal(t, []string{
+		`executed: recorded true, replayed false`,
+		`error: recorded "", replayed "timeout"`,
+		`body: recorded {"a":1,"b":[1,2]}, replayed "text"`,
+	}, Diff(recorded, &Response{Error: "timeout", Metadata: "m", Body: []byte("text")}, Fields))
+	require.Empty(t, Diff(recorded, &Response{Executed: true, Metadata: "other"}, []string{"executed"}))
+	require.Equal(t, []string{`tags: recorded null, replayed {"k":"v"}`}, Diff(recorded, &Response{Executed: true, Metadata: "m", Body: recorded.Body, Tags: map[string]string{"k": "v"}}, Fields))
+
+	require.NoError(t, ValidateFields([]string{"body", "tags"}))
+	require.Error(t, ValidateFields([]string{"latency"}))
+}
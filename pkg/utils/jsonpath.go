@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// JSONPath evaluates kubectl style jsonpath expressions on json documents. Expressions can be set with or without
+// the surrounding braces, i.e. {.status}, .status or $.status
+type JSONPath struct {
+	expr string
+	jp   *jsonpath.JSONPath
+}
+
+// NewJSONPath parses expr, keys missing from the document select no value instead of failing
+func NewJSONPath(expr string) (*JSONPath, error) {
+	template := strings.TrimSpace(expr)
+	if !strings.HasPrefix(template, "{") {
+		template = fmt.Sprintf("{%s}", template)
+	}
+	jp := jsonpath.New(expr)
+	jp.AllowMissingKeys(true)
+	if err := jp.Parse(template); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath expression %s, %s", expr, err.Error())
+	}
+	return &JSONPath{
+		expr: expr,
+		jp:   jp,
+	}, nil
+}
+
+// Values returns the string values selected by the expression, strings are returned as is and any other value as json.
+// Data that is not a valid json document returns no values
+func (j *JSONPath) Values(data []byte) []string {
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	results, err := j.jp.FindResults(obj)
+	if err != nil {
+		return nil
+	}
+	var values []string
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || !value.CanInterface() {
+				continue
+			}
+			switch v := value.Interface().(type) {
+			case nil:
+				values = append(values, "null")
+			case string:
+				values = append(values, v)
+			default:
+				b, err := json.Marshal(v)
+				if err != nil {
+					continue
+				}
+				values = append(values, string(b))
+			}
+		}
+	}
+	return values
+}
+
+func (j *JSONPath) String() string {
+	return j.expr
+}
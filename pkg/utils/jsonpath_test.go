@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath_Values(t *testing.T) {
+	data := []byte(`{"status":"cancelled","amount":12.5,"items":[{"sku":"a"},{"sku":"b"}],"note":null}`)
+	tests := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "string", expr: ".status", want: []string{"cancelled"}},
+		{name: "braces", expr: "{.status}", want: []string{"cancelled"}},
+		{name: "root", expr: "$.amount", want: []string{"12.5"}},
+		{name: "wildcard", expr: ".items[*].sku", want: []string{"a", "b"}},
+		{name: "null", expr: ".note", want: []string{"null"}},
+		{name: "missing", expr: ".missing", want: nil},
+		{name: "invalid", expr: ".items[", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := NewJSONPath(tt.expr)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, jp.Values(data))
+		})
+	}
+	jp, err := NewJSONPath(".status")
+	require.NoError(t, err)
+	require.Nil(t, jp.Values([]byte("not json")))
+}
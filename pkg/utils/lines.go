@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+)
+
+const maxLineSize = 4 * 1024 * 1024
+
+// ReadLines sends each non-empty line of r as it arrives until EOF or ctx is done, the lines channel is closed when
+// reading ends and the read error, if any, is sent on the error channel
+func ReadLines(ctx context.Context, r io.Reader) (<-chan string, <-chan error) {
+	linesCh := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(linesCh)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			line := strings.TrimSuffix(scanner.Text(), "\r")
+			if line == "" {
+				continue
+			}
+			select {
+			case linesCh <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errCh <- err
+		}
+	}()
+	return linesCh, errCh
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLines(t *testing.T) {
+	linesCh, errCh := ReadLines(context.Background(), strings.NewReader("first\n\nsecond\r\nthird"))
+	var lines []string
+	for line := range linesCh {
+		lines = append(lines, line)
+	}
+	require.Equal(t, []string{"first", "second", "third"}, lines)
+	require.Len(t, errCh, 0)
+
+	linesCh, errCh = ReadLines(context.Background(), strings.NewReader(strings.Repeat("x", maxLineSize+1)))
+	for range linesCh {
+	}
+	require.Error(t, <-errCh)
+}